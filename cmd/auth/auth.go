@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage OVH API credentials",
+		Long:  "Request and save the OVH API credentials used to place and track orders in an encrypted file, and audit their access rules",
+		Example: `  kimsufi-notifier auth login
+  kimsufi-notifier auth status --endpoint ovh-ca
+  kimsufi-notifier auth list`,
+	}
+
+	// Flags variables
+	params order.Params
+)
+
+// init registers all flags
+func init() {
+	order.BindCredentialsFlags(Cmd, &params)
+}
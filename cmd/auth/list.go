@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+)
+
+var (
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List saved credentials",
+		Long:  "List the endpoints with credentials saved by auth login, secrets are not shown",
+		Example: `  kimsufi-notifier auth list
+  kimsufi-notifier auth list --credentials-file ./credentials.age`,
+		Args: cobra.NoArgs,
+		RunE: listRunner,
+	}
+)
+
+// init registers the subcommand
+func init() {
+	Cmd.AddCommand(listCmd)
+}
+
+// listRunner is the main function for the auth list command
+func listRunner(cmd *cobra.Command, args []string) error {
+	store, err := order.CredentialsStore(params)
+	if err != nil {
+		return err
+	}
+
+	f, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("error: failed to read saved credentials: %w", err)
+	}
+
+	if len(f.Credentials) == 0 {
+		fmt.Printf("> no credentials saved in %s\n", store.Path())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "endpoint\tapp key\tsecret\tconsumer key\tcreated")
+	fmt.Fprintln(w, "--------\t-------\t------\t------------\t-------")
+	for _, c := range f.Credentials {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Endpoint, mask(c.AppKey), saved(c.AppSecret), mask(c.ConsumerKey), c.Created.Format(time.DateTime))
+	}
+	w.Flush()
+
+	return nil
+}
+
+// mask hides all but the first characters of a key.
+func mask(key string) string {
+	const visible = 4
+	if len(key) <= visible {
+		return "****"
+	}
+
+	return key[:visible] + "****"
+}
+
+// saved tells whether a secret is saved.
+func saved(secret string) string {
+	if secret == "" {
+		return "no"
+	}
+
+	return "yes"
+}
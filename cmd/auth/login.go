@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/credentials"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiauthentication "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/authentication"
+)
+
+const (
+	validationCheckInterval = 5 * time.Second
+)
+
+var (
+	loginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Request a consumer key",
+		Long:  "Request a consumer key granted the access rules needed to place and track orders, wait until it is validated at the printed URL and save it with the application key and secret in the encrypted credentials file\n\nthe application key and secret are read from the environment variables named by --ovh-app-key and --ovh-app-secret, or from the credentials saved for the endpoint when they are not set",
+		Example: `  kimsufi-notifier auth login
+  kimsufi-notifier auth login --endpoint ovh-us --timeout 5m`,
+		Args: cobra.NoArgs,
+		RunE: loginRunner,
+	}
+
+	// Flags variables
+	loginTimeout time.Duration
+)
+
+// init registers the subcommand and its flags
+func init() {
+	loginCmd.Flags().DurationVar(&loginTimeout, "timeout", 10*time.Minute, "maximum time to wait for the consumer key validation")
+
+	Cmd.AddCommand(loginCmd)
+}
+
+// loginRunner is the main function for the auth login command
+func loginRunner(cmd *cobra.Command, args []string) error {
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	params.Endpoint = endpoint
+
+	store, err := order.CredentialsStore(params)
+	if err != nil {
+		return err
+	}
+
+	c, err := order.ReadApplication(params, store)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	appKey, appSecret := c.AppKey, c.AppSecret
+
+	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	app, err := k.WithAuth(appKey, appSecret, "")
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	req, err := app.RequestCredential(kimsufiauthentication.RequiredRules)
+	if err != nil {
+		return fmt.Errorf("error: failed to request consumer key: %w", err)
+	}
+
+	fmt.Println("> consumer key requested with access to:")
+	for _, rule := range kimsufiauthentication.RequiredRules {
+		fmt.Printf("  %s %s\n", rule.Method, rule.Path)
+	}
+	fmt.Printf("> open %s to validate it\n", req.ValidationURL)
+
+	authenticated, err := k.WithAuth(appKey, appSecret, req.ConsumerKey)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	err = waitValidation(cmd.Context(), authenticated, loginTimeout)
+	if err != nil {
+		return err
+	}
+
+	err = store.Set(credentials.Credentials{
+		Endpoint:    endpoint,
+		AppKey:      appKey,
+		AppSecret:   appSecret,
+		ConsumerKey: req.ConsumerKey,
+		Created:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("error: failed to save credentials: %w", err)
+	}
+
+	fmt.Printf("> consumer key validated and saved with the application credentials to %s\n", store.Path())
+
+	return nil
+}
+
+// waitValidation waits until the consumer key of k is validated, refused or expired.
+func waitValidation(ctx context.Context, k *kimsufi.Service, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	fmt.Println("> waiting for validation")
+	for {
+		// The credential can not be read until it is validated.
+		credential, err := k.GetCurrentCredentialCtx(ctx)
+		if err != nil {
+			log.Debugf("consumer key not validated yet: %v", err)
+		} else {
+			switch credential.Status {
+			case kimsufiauthentication.CredentialStatusValidated:
+				return nil
+			case kimsufiauthentication.CredentialStatusRefused, kimsufiauthentication.CredentialStatusExpired:
+				return fmt.Errorf("error: consumer key %s", credential.Status)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error: consumer key not validated: %w", context.Cause(ctx))
+		case <-time.After(validationCheckInterval):
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+)
+
+var (
+	removeCmd = &cobra.Command{
+		Use:   "remove [endpoint]",
+		Short: "Remove saved credentials",
+		Long:  "Remove the credentials saved by auth login for the endpoint, the --endpoint flag value when not given\n\nthe consumer key is only removed locally, it stays valid until it expires or is revoked in the OVH account",
+		Example: `  kimsufi-notifier auth remove
+  kimsufi-notifier auth remove ovh-ca`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: removeRunner,
+	}
+)
+
+// init registers the subcommand
+func init() {
+	Cmd.AddCommand(removeCmd)
+}
+
+// removeRunner is the main function for the auth remove command
+func removeRunner(cmd *cobra.Command, args []string) error {
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	if len(args) > 0 {
+		endpoint = args[0]
+	}
+
+	store, err := order.CredentialsStore(params)
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Remove(endpoint)
+	if err != nil {
+		return fmt.Errorf("error: failed to remove credentials: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("error: no credentials saved for %s in %s", endpoint, store.Path())
+	}
+
+	fmt.Printf("> credentials for %s removed from %s\n", endpoint, store.Path())
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiauthentication "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/authentication"
+)
+
+var (
+	statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the credential and audit its access rules",
+		Long:  "Show the application, status, expiration and last use of the OVH API credential, and check that its access rules cover the calls made to place, track and pay orders",
+		Example: `  kimsufi-notifier auth status
+  kimsufi-notifier auth status --endpoint ovh-ca`,
+		Args: cobra.NoArgs,
+		RunE: statusRunner,
+	}
+)
+
+// init registers the subcommand
+func init() {
+	Cmd.AddCommand(statusCmd)
+}
+
+// statusRunner is the main function for the auth status command
+func statusRunner(cmd *cobra.Command, args []string) error {
+	params.Endpoint = cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+
+	k, err := kimsufi.NewService(params.Endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	k, err = order.Authenticate(k, params)
+	if err != nil {
+		return err
+	}
+
+	credential, err := k.GetCurrentCredential()
+	if err != nil {
+		return fmt.Errorf("error: failed to get current credential: %w", err)
+	}
+
+	application := strconv.Itoa(credential.ApplicationID)
+	app, err := k.GetApplication(credential.ApplicationID)
+	if err != nil {
+		log.Debugf("failed to get application %d: %v", credential.ApplicationID, err)
+	} else {
+		application = fmt.Sprintf("%s (%d)", app.Name, app.ApplicationID)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "endpoint\t%s\n", params.Endpoint)
+	fmt.Fprintf(w, "application\t%s\n", application)
+	fmt.Fprintf(w, "credential\t%d\n", credential.CredentialID)
+	fmt.Fprintf(w, "status\t%s\n", credential.Status)
+	fmt.Fprintf(w, "created\t%s\n", orNever(credential.Creation))
+	fmt.Fprintf(w, "expiration\t%s\n", orNever(credential.Expiration))
+	fmt.Fprintf(w, "last use\t%s\n", orNever(credential.LastUse))
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "feature\tmethod\tpath\tallowed")
+	fmt.Fprintln(w, "-------\t------\t----\t-------")
+	for _, call := range kimsufiauthentication.RequiredCalls {
+		allowed := "yes"
+		if !credential.Covers(call.Method, call.Path) {
+			allowed = "MISSING"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", call.Feature, call.Method, call.Path, allowed)
+	}
+	w.Flush()
+
+	if credential.Status != kimsufiauthentication.CredentialStatusValidated {
+		return fmt.Errorf("error: credential is %s, run auth login", credential.Status)
+	}
+
+	missing := credential.MissingCalls(kimsufiauthentication.RequiredCalls)
+	if len(missing) > 0 {
+		return fmt.Errorf("error: %d call(s) not allowed by the credential rules, run auth login", len(missing))
+	}
+
+	return nil
+}
+
+// orNever returns value, or never when it is empty.
+func orNever(value string) string {
+	if value == "" {
+		return "never"
+	}
+
+	return value
+}
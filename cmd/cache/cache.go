@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:     "cache",
+		Short:   "Manage the OVH API cache",
+		Long:    "Manage the OVH API responses cached on disk, such as the public catalogs, in $XDG_CACHE_HOME/kimsufi-notifier",
+		Example: `  kimsufi-notifier cache clear`,
+	}
+)
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/httpcache"
+)
+
+var (
+	clearCmd = &cobra.Command{
+		Use:     "clear",
+		Short:   "Remove the cached OVH API responses",
+		Long:    "Remove all the OVH API responses cached on disk, the next commands fetch them again",
+		Example: `  kimsufi-notifier cache clear`,
+		Args:    cobra.NoArgs,
+		RunE:    clearRunner,
+	}
+)
+
+// init registers the subcommand
+func init() {
+	Cmd.AddCommand(clearCmd)
+}
+
+// clearRunner is the main function for the cache clear command
+func clearRunner(cmd *cobra.Command, args []string) error {
+	c, err := httpcache.NewCache("", httpcache.DefaultRules)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	count, err := c.Clear()
+	if err != nil {
+		return fmt.Errorf("error: failed to clear cache: %w", err)
+	}
+
+	fmt.Printf("> %d cached responses removed from %s\n", count, c.Dir())
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/catalogdiff"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/pricehistory"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "catalog",
+		Short: "Track catalog changes",
+		Long:  "Save snapshots of the Eco and VPS catalogs and compare them over time",
+	}
+
+	// Flags variables
+	kinds        []string
+	pricesFile   string
+	snapshotsDir string
+)
+
+// Refreshed is the result of a catalog refresh.
+type Refreshed struct {
+	// Snapshot is the current catalog.
+	Snapshot catalogdiff.Snapshot
+	// Diff holds the differences with the previous catalog, it is nil when no catalog was saved before.
+	Diff *catalogdiff.Diff
+	// Prices holds the recorded price changes.
+	Prices pricehistory.Changes
+}
+
+// init registers all flags
+func init() {
+	Cmd.PersistentFlags().StringSliceVar(&kinds, "kind", catalogdiff.Kinds, fmt.Sprintf("catalog kind(s) to track, comma separated list (allowed values: %s)", strings.Join(catalogdiff.Kinds, ", ")))
+	Cmd.PersistentFlags().StringVar(&pricesFile, "prices-file", "", "price history file (default $XDG_DATA_HOME/kimsufi-notifier/prices.jsonl)")
+	Cmd.PersistentFlags().StringVar(&snapshotsDir, "snapshots-dir", "", "catalog snapshots directory (default $XDG_DATA_HOME/kimsufi-notifier/catalogs)")
+}
+
+// Refresh fetches the current catalog of the given kind, saves it, records its prices
+// and returns the differences with the previously saved one.
+// Prices are not recorded when prices is nil.
+// The catalog is always fetched from the API, never from the disk cache.
+func Refresh(k *kimsufi.Service, store *catalogdiff.Store, prices *pricehistory.Store, endpoint, subsidiary, kind string, now time.Time) (*Refreshed, error) {
+	k = k.WithRefresh()
+
+	previous, err := store.LoadSnapshot(endpoint, subsidiary, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s catalog snapshot: %w", kind, err)
+	}
+
+	var (
+		raw      any
+		r        Refreshed
+		observed pricehistory.Prices
+	)
+	switch kind {
+	case catalogdiff.KindEco:
+		c, err := k.ListServers(subsidiary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list servers: %w", err)
+		}
+		raw, r.Snapshot = c, catalogdiff.NewSnapshotFromCatalog(endpoint, now, *c)
+		observed = pricehistory.NewPricesFromCatalog(endpoint, now, *c)
+	case catalogdiff.KindVPS:
+		c, err := k.ListVPSServers(subsidiary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list VPS servers: %w", err)
+		}
+		raw, r.Snapshot = c, catalogdiff.NewSnapshotFromVPSCatalog(endpoint, now, *c)
+		observed = pricehistory.NewPricesFromVPSCatalog(endpoint, now, *c)
+	default:
+		return nil, fmt.Errorf("invalid catalog kind %q (allowed values: %s)", kind, strings.Join(catalogdiff.Kinds, ", "))
+	}
+
+	err = store.Save(endpoint, subsidiary, kind, now, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save %s catalog snapshot: %w", kind, err)
+	}
+
+	if prices != nil {
+		r.Prices, err = prices.Record(observed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record %s catalog prices: %w", kind, err)
+		}
+	}
+
+	if previous != nil {
+		diff := catalogdiff.Compare(*previous, r.Snapshot)
+		r.Diff = &diff
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/catalogdiff"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/pricehistory"
+)
+
+var (
+	diffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Show catalog changes since the last run",
+		Long:  "Fetch the current catalogs, compare them with the snapshots saved by the previous run and report new, removed and changed plans\n\nchanges cover prices, addon families and datacenters, each changed plan is also emitted as a notification event\n\nthe first price of each plan and addon is recorded in the price history and checked against the --price-rule alert rules",
+		Example: `  kimsufi-notifier catalog diff
+  kimsufi-notifier catalog diff --kind eco --country US --endpoint ovh-us
+  kimsufi-notifier catalog diff --notify-webhook https://example.com/hook
+  kimsufi-notifier catalog diff --price-rule plan=24sk50,subsidiary=FR,below=20 --price-rule category=rise,change`,
+		RunE: diffRunner,
+	}
+
+	// Flags variables
+	outputFormat string
+	priceRules   []string
+	webhooks     []string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	flag.BindOutputFlag(diffCmd, &outputFormat)
+	flag.BindNotifyWebhookFlag(diffCmd, &webhooks)
+
+	diffCmd.Flags().StringArrayVar(&priceRules, "price-rule", nil, "price alert rule, as comma separated filters (plan, category, subsidiary, kind, type) and a condition (below=<price>, change, drop), can be repeated (e.g. plan=24sk50,subsidiary=FR,below=20)")
+
+	Cmd.AddCommand(diffCmd)
+}
+
+// diffRunner is the main function for the catalog diff command
+func diffRunner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range kinds {
+		if !slices.Contains(catalogdiff.Kinds, kind) {
+			return fmt.Errorf("invalid --kind %q (allowed values: %s)", kind, strings.Join(catalogdiff.Kinds, ", "))
+		}
+	}
+
+	rules, err := pricehistory.ParseRules(priceRules)
+	if err != nil {
+		return err
+	}
+
+	// Initialize kimsufi service
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	store, err := catalogdiff.NewStore(snapshotsDir)
+	if err != nil {
+		return err
+	}
+
+	prices, err := pricehistory.NewStore(pricesFile)
+	if err != nil {
+		return err
+	}
+
+	subsidiary := cmd.Flag(flag.CountryFlagName).Value.String()
+	// Events are printed to stderr, stdout holds the changes in the output format.
+	n := notifier.New(os.Stderr, webhooks)
+	now := time.Now()
+
+	diffs := []catalogdiff.Diff{}
+	for _, kind := range kinds {
+		r, err := Refresh(k, store, prices, endpoint, subsidiary, kind, now)
+		if err != nil {
+			return err
+		}
+
+		alerts := pricehistory.Events(rules.Evaluate(r.Prices), now)
+
+		err = notifier.NotifyAll(n, alerts)
+		if err != nil {
+			log.Warnf("failed to notify %s catalog price alerts: %v", kind, err)
+		}
+
+		if r.Diff == nil {
+			fmt.Fprintf(os.Stderr, "saved first %s %s catalog snapshot with %d plans, changes are reported from the next run\n", subsidiary, kind, len(r.Snapshot.Plans))
+			continue
+		}
+
+		diffs = append(diffs, *r.Diff)
+
+		err = notifier.NotifyAll(n, r.Diff.Events(now))
+		if err != nil {
+			log.Warnf("failed to notify %s catalog changes: %v", kind, err)
+		}
+	}
+
+	headers := []string{"kind", "planCode", "name", "change", "field", "old", "new"}
+	var rows [][]string
+	for _, d := range diffs {
+		for _, plan := range d.Added {
+			rows = append(rows, []string{d.Kind, plan.PlanCode, plan.InvoiceName, "added", "", "", ""})
+		}
+		for _, plan := range d.Removed {
+			rows = append(rows, []string{d.Kind, plan.PlanCode, plan.InvoiceName, "removed", "", "", ""})
+		}
+		for _, plan := range d.Changed {
+			for _, c := range plan.Changes {
+				rows = append(rows, []string{d.Kind, plan.PlanCode, plan.InvoiceName, "changed", c.Field, c.Old, c.New})
+			}
+		}
+	}
+
+	if outputFormat == output.FormatTable && len(rows) == 0 {
+		if len(diffs) > 0 {
+			fmt.Println("no catalog changes")
+		}
+		return nil
+	}
+
+	return output.Write(os.Stdout, outputFormat, headers, rows, diffs)
+}
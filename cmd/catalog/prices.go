@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/pricehistory"
+)
+
+var (
+	pricesCmd = &cobra.Command{
+		Use:   "prices",
+		Short: "Show the price history",
+		Long:  "List the plan and addon prices recorded by catalog diff, one line per price change",
+		Example: `  kimsufi-notifier catalog prices --plan-code 24sk50
+  kimsufi-notifier catalog prices --category rise --since 90d --output csv`,
+		RunE: pricesRunner,
+	}
+
+	// Flags variables
+	pricesCategory     string
+	pricesOutputFormat string
+	pricesPlanCode     string
+	pricesSince        string
+	pricesTimezone     string
+	pricesUntil        string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	flag.BindPlanCodeFlag(pricesCmd, &pricesPlanCode)
+	flag.BindOutputFlag(pricesCmd, &pricesOutputFormat)
+	flag.BindTimeRangeFlags(pricesCmd, &pricesSince, &pricesUntil)
+	flag.BindTimezoneFlag(pricesCmd, &pricesTimezone)
+
+	pricesCmd.PersistentFlags().StringVar(&pricesCategory, flag.CategoryFlagName, "", "category to filter on (e.g. kimsufi, rise)")
+
+	Cmd.AddCommand(pricesCmd)
+}
+
+// pricesRunner is the main function for the catalog prices command
+func pricesRunner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(pricesOutputFormat)
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(pricesTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.TimezoneFlagName, err)
+	}
+
+	now := time.Now()
+	sinceTime, err := history.ParseTime(pricesSince, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.SinceFlagName, err)
+	}
+	untilTime, err := history.ParseTime(pricesUntil, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.UntilFlagName, err)
+	}
+
+	filter := pricehistory.Filter{
+		PlanCode: pricesPlanCode,
+		Category: pricesCategory,
+		Since:    sinceTime,
+		Until:    untilTime,
+	}
+
+	if flag.IsSet(cmd, flag.OVHAPIEndpointFlagName) {
+		filter.Endpoint = cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	}
+	if flag.IsSet(cmd, flag.CountryFlagName) {
+		filter.Subsidiary = cmd.Flag(flag.CountryFlagName).Value.String()
+	}
+
+	store, err := pricehistory.NewStore(pricesFile)
+	if err != nil {
+		return err
+	}
+
+	prices, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load price history: %w", err)
+	}
+
+	// Changes are computed before filtering so that the first change in the time range keeps its previous price.
+	changes := prices.Changes().Filter(filter)
+	if changes == nil {
+		changes = pricehistory.Changes{}
+	}
+
+	headers := []string{"time", "subsidiary", "kind", "type", "planCode", "name", "price", "previous", "delta"}
+	var rows [][]string
+	for _, c := range changes {
+		p := c.Current
+
+		var previous, delta string
+		if c.Previous != nil {
+			previous = c.Previous.String()
+			delta = fmt.Sprintf("%+.2f", c.Delta())
+		}
+
+		rows = append(rows, []string{p.Time.In(loc).Format(time.DateTime), p.Subsidiary, p.Kind, p.Type, p.PlanCode, p.InvoiceName, p.String(), previous, delta})
+	}
+
+	return output.Write(os.Stdout, pricesOutputFormat, headers, rows, changes)
+}
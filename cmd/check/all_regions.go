@@ -0,0 +1,215 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+// regionCheck is the price and availability of the plan in a subsidiary.
+type regionCheck struct {
+	kimsufi.Target
+	// Sold is false when the plan is not in the subsidiary catalog.
+	Sold        bool
+	Name        string
+	Price       float64
+	Currency    string
+	Status      string
+	Datacenters []string
+}
+
+// runnerAllRegions checks the plan in every endpoint and country, sorted by price.
+func runnerAllRegions(cmd *cobra.Command) error {
+	if recordHistory || listDatacenters || listOptions {
+		return fmt.Errorf("--%s can not be used with --record, --list-datacenters or --list-options", flag.AllRegionsFlagName)
+	}
+
+	m, err := kimsufi.NewMultiService(log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	m = m.WithContext(cmd.Context())
+
+	var results []kimsufi.TargetResult[regionCheck]
+	if kimsufi.IsVPSPlanCode(planCode) {
+		results = kimsufi.FanOut(m, m.Targets(), parallelism, checkVPSRegion)
+	} else {
+		results = checkEcoRegions(m)
+	}
+
+	var checks []regionCheck
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			log.Warnf("failed to check %s in %s/%s: %v", planCode, r.Endpoint, r.Subsidiary, r.Err)
+			errs = append(errs, r.Err)
+			continue
+		}
+		if r.Value.Sold {
+			checks = append(checks, r.Value)
+		}
+	}
+	if len(errs) == len(results) {
+		return fmt.Errorf("error: %w", errors.Join(errs...))
+	}
+	if len(checks) == 0 {
+		return fmt.Errorf("plan %s not found in any country", planCode)
+	}
+
+	// Sort by availability first, then currency and price, prices of different currencies are not comparable.
+	sort.SliceStable(checks, func(i, j int) bool {
+		availableI := checks[i].Status == kimsufiavailability.StatusAvailable
+		availableJ := checks[j].Status == kimsufiavailability.StatusAvailable
+		if availableI != availableJ {
+			return availableI
+		}
+		if checks[i].Currency != checks[j].Currency {
+			return checks[i].Currency < checks[j].Currency
+		}
+
+		return checks[i].Price < checks[j].Price
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "planCode\tendpoint\tcountry\tprice\tstatus\tdatacenters")
+	fmt.Fprintln(w, "--------\t--------\t-------\t-----\t------\t-----------")
+
+	nothingAvailable := true
+	for _, c := range checks {
+		if c.Status == kimsufiavailability.StatusAvailable {
+			nothingAvailable = false
+		}
+
+		name := planCode
+		if humanLevel > 0 && c.Name != "" {
+			name = c.Name
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f %s\t%s\t%s\n", name, c.Endpoint, c.Subsidiary, c.Price, c.Currency, c.Status, strings.Join(c.Datacenters, ", "))
+	}
+	w.Flush()
+
+	if nothingAvailable {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// checkEcoRegions checks the Eco plan in every subsidiary of m, the availabilities are fetched once per endpoint.
+func checkEcoRegions(m kimsufi.MultiService) []kimsufi.TargetResult[regionCheck] {
+	availabilities := kimsufi.FanOut(m, m.EndpointTargets(), parallelism, func(k *kimsufi.Service, t kimsufi.Target) (kimsufiavailability.Datacenters, error) {
+		a, err := k.GetAvailabilities(datacenters, planCode, options)
+		if kimsufi.IsAvailabilityNotFoundError(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return a.GetAvailableDatacenters(), nil
+	})
+
+	available := make(map[string]kimsufiavailability.Datacenters)
+	failed := make(map[string]error)
+	for _, r := range availabilities {
+		if r.Err != nil {
+			failed[r.Endpoint] = r.Err
+			continue
+		}
+		available[r.Endpoint] = r.Value
+	}
+
+	return kimsufi.FanOut(m, m.Targets(), parallelism, func(k *kimsufi.Service, t kimsufi.Target) (regionCheck, error) {
+		if err, ok := failed[t.Endpoint]; ok {
+			return regionCheck{}, err
+		}
+
+		catalog, err := k.ListServers(t.Subsidiary)
+		if err != nil {
+			return regionCheck{}, err
+		}
+
+		plan := catalog.GetPlan(planCode)
+		if plan == nil {
+			return regionCheck{Target: t}, nil
+		}
+
+		c := regionCheck{
+			Target:   t,
+			Sold:     true,
+			Name:     strings.Split(plan.InvoiceName, " | ")[0],
+			Currency: catalog.Locale.CurrencyCode,
+			Status:   available[t.Endpoint].Status(),
+		}
+
+		planPrice := plan.GetFirstPrice()
+		if !reflect.DeepEqual(planPrice, kimsuficatalog.PlanPricing{}) {
+			c.Price = planPrice.GetPrice()
+		}
+
+		if humanLevel > 1 {
+			c.Datacenters = available[t.Endpoint].ToFullNamesOrCodes()
+		} else {
+			c.Datacenters = available[t.Endpoint].Codes()
+		}
+
+		return c, nil
+	})
+}
+
+// checkVPSRegion checks the VPS plan in the target subsidiary.
+func checkVPSRegion(k *kimsufi.Service, t kimsufi.Target) (regionCheck, error) {
+	catalog, err := k.ListVPSServers(t.Subsidiary)
+	if err != nil {
+		return regionCheck{}, err
+	}
+
+	plan := catalog.GetVPSPlan(planCode)
+	if plan == nil {
+		return regionCheck{Target: t}, nil
+	}
+
+	c := regionCheck{
+		Target:   t,
+		Sold:     true,
+		Name:     plan.InvoiceName,
+		Currency: catalog.Locale.CurrencyCode,
+		Status:   kimsufiavailability.StatusUnavailable,
+	}
+
+	planPrice := plan.GetFirstPrice()
+	if !reflect.DeepEqual(planPrice, kimsuficatalog.VPSPricing{}) {
+		c.Price = planPrice.GetPrice()
+	}
+
+	availabilities, err := k.GetVPSAvailabilities(planCode, t.Subsidiary, "")
+	if err != nil {
+		return regionCheck{}, err
+	}
+
+	for _, dc := range availabilities.GetAvailableDatacenterCodes() {
+		if len(datacenters) > 0 && !slices.ContainsFunc(datacenters, func(d string) bool { return strings.EqualFold(d, dc) }) {
+			continue
+		}
+		c.Datacenters = append(c.Datacenters, dc)
+	}
+	if len(c.Datacenters) > 0 {
+		c.Status = kimsufiavailability.StatusAvailable
+	}
+
+	return c, nil
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
 	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
 	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
 	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
@@ -23,7 +25,9 @@ var (
 		Long:  "Check OVH Eco (including Kimsufi) server and VPS availability\n\ndatacenters are the available datacenters for this plan",
 		Example: `  kimsufi-notifier check --plan-code 24ska01
   kimsufi-notifier check --plan-code 24ska01 --datacenters gra,rbx
-  kimsufi-notifier check --plan-code vps-starter-1-2-20 --country FR`,
+  kimsufi-notifier check --plan-code vps-starter-1-2-20 --country FR
+  kimsufi-notifier check --plan-code 24ska01 --record
+  kimsufi-notifier check --plan-code 24ska01 --all-regions`,
 		RunE: runner,
 	}
 
@@ -35,6 +39,12 @@ var (
 
 	listDatacenters bool
 	listOptions     bool
+
+	historyFile   string
+	recordHistory bool
+
+	allRegions  bool
+	parallelism int
 )
 
 // init registers all flags
@@ -42,9 +52,12 @@ func init() {
 	flag.BindPlanCodeFlag(Cmd, &planCode)
 	flag.BindDatacentersFlag(Cmd, &datacenters)
 	flag.BindHumanFlag(Cmd, &humanLevel)
+	flag.BindHistoryFileFlag(Cmd, &historyFile)
+	flag.BindAllRegionsFlags(Cmd, &allRegions, &parallelism)
 
 	Cmd.PersistentFlags().BoolVar(&listDatacenters, "list-datacenters", false, "list available datacenters")
 	Cmd.PersistentFlags().BoolVar(&listOptions, "list-options", false, "list available item options")
+	Cmd.PersistentFlags().BoolVar(&recordHistory, "record", false, "record availability changes in the history file")
 	Cmd.PersistentFlags().StringToStringVarP(&options, "option", "o", nil, "options to filter on, comma separated list of key=value, see --list-options for available options (e.g. memory=ram-64g-noecc-2133)")
 }
 
@@ -56,16 +69,23 @@ func runner(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
+	k = k.WithContext(cmd.Context())
 
 	// Flag validation
 	if planCode == "" {
 		return fmt.Errorf("--%s is required", flag.PlanCodeFlagName)
 	}
 
-	// Check if this is a VPS plan code - VPS plans typically start with "vps-" or "s1-"
-	isVPSPlan := strings.HasPrefix(planCode, "vps-") || strings.HasPrefix(planCode, "s1-")
+	if allRegions {
+		err := flag.CheckAllRegionsFlags(cmd)
+		if err != nil {
+			return err
+		}
+		return runnerAllRegions(cmd)
+	}
 
-	if isVPSPlan {
+	// Check if this is a VPS plan code
+	if kimsufi.IsVPSPlanCode(planCode) {
 		return runnerVPS(cmd, k, planCode, datacenters)
 	}
 
@@ -98,6 +118,13 @@ func runner(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error: %w", err)
 	}
 
+	if recordHistory {
+		err = record(endpoint, history.NewObservationsFromAvailabilities(*availabilities))
+		if err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
 	// Display the server availabilities for each options.
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
 	fmt.Fprintln(w, "planCode\tmemory\tstorage\tstatus\tdatacenters")
@@ -153,6 +180,22 @@ func runner(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// record records the observations in the history file.
+func record(endpoint string, observations history.Observations) error {
+	store, err := history.NewStore(historyFile)
+	if err != nil {
+		return err
+	}
+
+	changes, err := store.Record(time.Now(), endpoint, observations)
+	if err != nil {
+		return err
+	}
+	log.Debugf("recorded %d availability changes in %s", len(changes), store.Path())
+
+	return nil
+}
+
 func datacenterAvailableMessageFormatter(datacenters []string) string {
 	var message string
 
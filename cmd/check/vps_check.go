@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
 	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
 )
 
@@ -23,6 +24,14 @@ func runnerVPS(cmd *cobra.Command, k *kimsufi.Service, planCode string, datacent
 		return fmt.Errorf("failed to get VPS availability for %s: %w", planCode, err)
 	}
 
+	if recordHistory {
+		endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+		err = record(endpoint, history.NewObservationsFromVPSAvailabilities(planCode, *vpsAvailabilities))
+		if err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
 	// Display VPS availability
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
 	fmt.Fprintln(w, "planCode\tdatacenter\tstatus\tlinuxStatus\twindowsStatus")
@@ -0,0 +1,125 @@
+package compareprices
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/pricecompare"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "compare-prices",
+		Short: "Compare plan prices across countries",
+		Long:  "Compare the monthly price, setup fee and tax rate of a plan in every country of every endpoint, cheapest first\n\nprices are in the currency of each country, use --rates-file to convert them to a base currency and compare them together",
+		Example: `  kimsufi-notifier compare-prices --plan-code 24ska01
+  kimsufi-notifier compare-prices --plan-code 24ska01 --rates-file rates.yaml
+  kimsufi-notifier compare-prices --plan-code 24ska01 --output json`,
+		Args: cobra.NoArgs,
+		RunE: runner,
+	}
+
+	// Flags variables
+	outputFormat string
+	parallelism  int
+	planCode     string
+	ratesFile    string
+)
+
+// init registers all flags
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &planCode)
+	flag.BindOutputFlag(Cmd, &outputFormat)
+
+	Cmd.PersistentFlags().IntVar(&parallelism, flag.ParallelismFlagName, kimsufi.ParallelismDefault, "maximum number of concurrent OVH API calls")
+	Cmd.PersistentFlags().StringVar(&ratesFile, "rates-file", "", "YAML rate table converting prices to a base currency, as the base currency and the value of one unit of each currency in it (e.g. base: EUR, rates: {GBP: 1.17, CAD: 0.68})")
+}
+
+// runner is the main function for the compare-prices command
+func runner(cmd *cobra.Command, args []string) error {
+	if planCode == "" {
+		return fmt.Errorf("--%s is required", flag.PlanCodeFlagName)
+	}
+	if kimsufi.IsVPSPlanCode(planCode) {
+		return fmt.Errorf("VPS plans are not supported, prices are compared from the Eco catalog")
+	}
+
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	var rates *pricecompare.RateTable
+	if ratesFile != "" {
+		rates, err = pricecompare.LoadRateTable(ratesFile)
+		if err != nil {
+			return fmt.Errorf("failed to load rate table: %w", err)
+		}
+	}
+
+	m, err := kimsufi.NewMultiService(log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	m = m.WithContext(cmd.Context())
+
+	offers, err := pricecompare.Compare(m, planCode, parallelism)
+	if err != nil {
+		if len(offers) == 0 {
+			return fmt.Errorf("error: %w", err)
+		}
+		log.Warnf("failed to list servers: %v", err)
+	}
+	if len(offers) == 0 {
+		return fmt.Errorf("plan %s not found in any country", planCode)
+	}
+
+	if rates != nil {
+		err = offers.Convert(*rates)
+		if err != nil {
+			log.Warnf("failed to convert prices: %v", err)
+		}
+	}
+	offers.Sort()
+
+	headers := []string{"endpoint", "country", "currency", "monthly", "setup", "taxRate", "monthlyWithTax", "setupWithTax"}
+	if rates != nil {
+		headers = append(headers, "monthly "+rates.Base, "setup "+rates.Base)
+	}
+
+	var rows [][]string
+	for _, o := range offers {
+		row := []string{
+			o.Endpoint,
+			o.Subsidiary,
+			o.Currency,
+			formatPrice(o.Monthly),
+			formatPrice(o.Setup),
+			strconv.Itoa(o.TaxRate) + "%",
+			formatPrice(o.MonthlyWithTax()),
+			formatPrice(o.SetupWithTax()),
+		}
+		if rates != nil {
+			if o.Base != nil {
+				row = append(row, formatPrice(o.Base.Monthly), formatPrice(o.Base.Setup))
+			} else {
+				row = append(row, "-", "-")
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return output.Write(os.Stdout, outputFormat, headers, rows, offers)
+}
+
+// formatPrice formats a price with two decimals.
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
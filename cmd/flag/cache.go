@@ -0,0 +1,57 @@
+package flag
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/httpcache"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+)
+
+const (
+	NoCacheFlagName = "no-cache"
+	RefreshFlagName = "refresh"
+)
+
+// BindCacheFlags binds the OVH API disk cache flags to the provided cmd.
+func BindCacheFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(NoCacheFlagName, false, "do not read nor write the OVH API responses cached in $XDG_CACHE_HOME/kimsufi-notifier")
+	cmd.PersistentFlags().Bool(RefreshFlagName, false, "ignore the cached OVH API responses and cache the new ones")
+}
+
+// ApplyCache sets the disk cache of the OVH API responses from the flags of cmd.
+func ApplyCache(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	noCache, err := flags.GetBool(NoCacheFlagName)
+	if err != nil {
+		return err
+	}
+
+	refresh, err := flags.GetBool(RefreshFlagName)
+	if err != nil {
+		return err
+	}
+
+	if noCache && refresh {
+		return fmt.Errorf("--%s and --%s can not be used together", NoCacheFlagName, RefreshFlagName)
+	}
+
+	if noCache {
+		kimsufi.SetDiskCache(nil)
+		return nil
+	}
+
+	c, err := httpcache.NewCache("", httpcache.DefaultRules)
+	if err != nil {
+		return err
+	}
+	if refresh {
+		c = c.WithRefresh()
+	}
+
+	kimsufi.SetDiskCache(c)
+
+	return nil
+}
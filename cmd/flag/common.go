@@ -8,7 +8,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/TheoBrigitte/kimsufi-notifier/pkg/category"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
 	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
 )
 
 const (
@@ -17,12 +19,25 @@ const (
 	DatacentersFlagName      = "datacenters"
 	DatacentersFlagShortName = "d"
 
+	HistoryFileFlagName = "history-file"
+
 	HumanFlagName      = "human"
 	HumanFlagShortName = "h"
 
 	PlanCodeFlagName      = "plan-code"
 	PlanCodeFlagShortName = "p"
 	PlanCodeExample       = "24ska01"
+
+	NotifyWebhookFlagName = "notify-webhook"
+
+	OutputFlagName = "output"
+
+	AllRegionsFlagName  = "all-regions"
+	ParallelismFlagName = "parallelism"
+
+	SinceFlagName    = "since"
+	UntilFlagName    = "until"
+	TimezoneFlagName = "timezone"
 )
 
 // BindCategoryFlag binds the country flag to the provided cmd and value.
@@ -39,6 +54,11 @@ func BindDatacentersFlag(cmd *cobra.Command, value *[]string) {
 	cmd.PersistentFlags().StringSliceVarP(value, DatacentersFlagName, DatacentersFlagShortName, nil, fmt.Sprintf("datacenter(s) to filter on, comma separated list (known values: %s)", strings.Join(kimsufiavailability.GetDatacentersKnownCodes(), ", ")))
 }
 
+// BindHistoryFileFlag binds the history file flag to the provided cmd and value.
+func BindHistoryFileFlag(cmd *cobra.Command, value *string) {
+	cmd.PersistentFlags().StringVar(value, HistoryFileFlagName, "", "availability history file (default $XDG_DATA_HOME/kimsufi-notifier/history.jsonl)")
+}
+
 // BindHumanFlag binds the verbose flag to the provided cmd and value.
 // Warning: this redefine the help flag to only be a long --help flag.
 func BindHumanFlag(cmd *cobra.Command, value *int) {
@@ -49,3 +69,41 @@ func BindHumanFlag(cmd *cobra.Command, value *int) {
 func BindPlanCodeFlag(cmd *cobra.Command, value *string) {
 	cmd.PersistentFlags().StringVarP(value, PlanCodeFlagName, PlanCodeFlagShortName, "", fmt.Sprintf("plan code name (e.g. %s)", PlanCodeExample))
 }
+
+// BindNotifyWebhookFlag binds the notification webhook flag to the provided cmd and value.
+func BindNotifyWebhookFlag(cmd *cobra.Command, value *[]string) {
+	cmd.PersistentFlags().StringSliceVar(value, NotifyWebhookFlagName, nil, "webhook URL(s) to post events to as JSON, comma separated list")
+}
+
+// BindOutputFlag binds the output format flag to the provided cmd and value.
+func BindOutputFlag(cmd *cobra.Command, value *string) {
+	cmd.PersistentFlags().StringVar(value, OutputFlagName, output.FormatTable, fmt.Sprintf("output format (allowed values: %s)", strings.Join(output.Formats, ", ")))
+}
+
+// BindTimeRangeFlags binds the since and until flags to the provided cmd and values.
+func BindTimeRangeFlags(cmd *cobra.Command, since, until *string) {
+	cmd.PersistentFlags().StringVar(since, SinceFlagName, "", "only include events after this time, as a date, a RFC 3339 timestamp or a duration ago (e.g. 2024-01-31, 7d, 36h)")
+	cmd.PersistentFlags().StringVar(until, UntilFlagName, "", "only include events before this time, same format as --since")
+}
+
+// BindTimezoneFlag binds the timezone flag to the provided cmd and value.
+func BindTimezoneFlag(cmd *cobra.Command, value *string) {
+	cmd.PersistentFlags().StringVar(value, TimezoneFlagName, "Local", "timezone used to display times (e.g. UTC, Europe/Paris)")
+}
+
+// BindAllRegionsFlags binds the all regions and parallelism flags to the provided cmd and values.
+func BindAllRegionsFlags(cmd *cobra.Command, allRegions *bool, parallelism *int) {
+	cmd.PersistentFlags().BoolVar(allRegions, AllRegionsFlagName, false, "query every endpoint and country concurrently and merge the results, can not be used with --endpoint or --country")
+	cmd.PersistentFlags().IntVar(parallelism, ParallelismFlagName, kimsufi.ParallelismDefault, "maximum number of concurrent OVH API calls with --all-regions")
+}
+
+// CheckAllRegionsFlags returns an error when the endpoint or country flags are given on the command line along with the all regions flag.
+func CheckAllRegionsFlags(cmd *cobra.Command) error {
+	for _, name := range []string{OVHAPIEndpointFlagName, CountryFlagName} {
+		if cmd.Flag(name).Changed {
+			return fmt.Errorf("--%s can not be used with --%s", AllRegionsFlagName, name)
+		}
+	}
+
+	return nil
+}
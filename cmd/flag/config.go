@@ -0,0 +1,120 @@
+package flag
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/config"
+)
+
+const (
+	ConfigFlagName  = "config"
+	ProfileFlagName = "profile"
+
+	// configAnnotation marks the flags set from their environment variable or from the profile, see IsSet.
+	configAnnotation = "kimsufi-notifier/config"
+)
+
+// BindConfigFlags binds the configuration file and profile flags to the provided cmd.
+func BindConfigFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(ConfigFlagName, "", "configuration file (default $XDG_CONFIG_HOME/kimsufi-notifier/config.yaml)")
+	cmd.PersistentFlags().String(ProfileFlagName, "", fmt.Sprintf("configuration profile, the default profile of the configuration file when unset (env %s)", config.ProfileEnvVarName))
+}
+
+// ApplyConfig sets the flags of cmd which are not set on the command line,
+// from their environment variable (see config.EnvVarName), then from the selected profile.
+func ApplyConfig(cmd *cobra.Command) error {
+	c, err := config.Load(cmd.Flag(ConfigFlagName).Value.String())
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	name := cmd.Flag(ProfileFlagName).Value.String()
+	if name == "" {
+		name = os.Getenv(config.ProfileEnvVarName)
+	}
+
+	profile, err := c.Profile(name)
+	if err != nil {
+		return err
+	}
+
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+
+	values := make(map[string][]string)
+	if profile != nil {
+		values, err = profile.Values(command)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+
+		for flagName := range profile.Commands[command] {
+			if cmd.Flags().Lookup(flagName) == nil {
+				return fmt.Errorf("profile %s: unknown flag %q for %s", name, flagName, command)
+			}
+		}
+	}
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		delete(f.Annotations, configAnnotation)
+
+		if err != nil || f.Changed || f.Name == ConfigFlagName || f.Name == ProfileFlagName || f.Name == "help" {
+			return
+		}
+
+		if value, ok := os.LookupEnv(config.EnvVarName(f.Name)); ok {
+			err = f.Value.Set(value)
+			if err != nil {
+				err = fmt.Errorf("invalid %s: %w", config.EnvVarName(f.Name), err)
+				return
+			}
+			setFromConfig(f, "env")
+			return
+		}
+
+		if value, ok := values[f.Name]; ok {
+			err = setValues(f, value)
+			if err != nil {
+				err = fmt.Errorf("profile %s: invalid %s: %w", name, f.Name, err)
+				return
+			}
+			setFromConfig(f, "profile")
+		}
+	})
+
+	return err
+}
+
+// IsSet returns true if the flag of cmd was set on the command line, from its environment variable or from the profile.
+// It is false when the flag has its default value.
+func IsSet(cmd *cobra.Command, name string) bool {
+	f := cmd.Flag(name)
+	if f == nil {
+		return false
+	}
+
+	_, fromConfig := f.Annotations[configAnnotation]
+
+	return f.Changed || fromConfig
+}
+
+// setFromConfig records that the flag was set from source, see IsSet.
+func setFromConfig(f *pflag.Flag, source string) {
+	if f.Annotations == nil {
+		f.Annotations = make(map[string][]string)
+	}
+	f.Annotations[configAnnotation] = []string{source}
+}
+
+// setValues sets the flag to the values, without marking it as set on the command line.
+func setValues(f *pflag.Flag, values []string) error {
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		return s.Replace(values)
+	}
+
+	return f.Value.Set(strings.Join(values, ","))
+}
@@ -0,0 +1,85 @@
+package flag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConfigPrecedence(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte(`
+defaultProfile: test
+profiles:
+  test:
+    endpoint: ovh-ca
+    country: CA
+    commands:
+      run:
+        plan-code: 24ska01
+        datacenters: [bhs]
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KIMSUFI_NOTIFIER_COUNTRY", "US")
+	t.Setenv("KIMSUFI_NOTIFIER_PLAN_CODE", "24sk10")
+
+	got := map[string]string{}
+	set := map[string]bool{}
+	root := &cobra.Command{Use: "root"}
+	root.PersistentFlags().StringP(OVHAPIEndpointFlagName, OVHAPIEndpointFlagShortName, OVHAPIEndpointDefault, "")
+	root.PersistentFlags().StringP(CountryFlagName, CountryFlagShortName, CountryDefault, "")
+	root.PersistentFlags().String(LogLevelFlagName, "error", "")
+	BindConfigFlags(root)
+
+	run := &cobra.Command{
+		Use: "run",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return ApplyConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range []string{OVHAPIEndpointFlagName, CountryFlagName, PlanCodeFlagName, "datacenters", LogLevelFlagName} {
+				got[name] = cmd.Flag(name).Value.String()
+				set[name] = IsSet(cmd, name)
+			}
+			return nil
+		},
+	}
+	run.Flags().String(PlanCodeFlagName, "", "")
+	run.Flags().StringSlice("datacenters", nil, "")
+	root.AddCommand(run)
+
+	root.SetArgs([]string{"run", "--config", configFile, "--plan-code", "24sk50"})
+	err = root.Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// command line > environment > profile > default
+	expected := map[string]string{
+		OVHAPIEndpointFlagName: "ovh-ca",
+		CountryFlagName:        "US",
+		PlanCodeFlagName:       "24sk50",
+		"datacenters":          "[bhs]",
+		LogLevelFlagName:       "error",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("flag values mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedSet := map[string]bool{
+		OVHAPIEndpointFlagName: true,
+		CountryFlagName:        true,
+		PlanCodeFlagName:       true,
+		"datacenters":          true,
+		LogLevelFlagName:       false,
+	}
+	if diff := cmp.Diff(expectedSet, set); diff != "" {
+		t.Errorf("IsSet() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -24,6 +24,11 @@ const (
 	CountryFlagName      = "country"
 	CountryFlagShortName = "c"
 	CountryDefault       = "FR"
+
+	RetriesFlagName       = "retries"
+	RetryMaxDelayFlagName = "retry-max-delay"
+	RateLimitFlagName     = "rate-limit"
+	RateBurstFlagName     = "rate-burst"
 )
 
 // Bind binds the global flags to the provided cmd.
@@ -51,4 +56,53 @@ func Bind(cmd *cobra.Command) {
 	}
 
 	cmd.PersistentFlags().StringP(CountryFlagName, CountryFlagShortName, CountryDefault, fmt.Sprintf("country code, known values per endpoints:\n%s", output.String()))
+
+	// OVH API retries and rate limit
+	cmd.PersistentFlags().Int(RetriesFlagName, kimsufi.DefaultRetryPolicy.MaxAttempts-1, "maximum number of retries of a failed OVH API call, only idempotent calls are retried on server errors, other calls like checkout only when rejected with 429")
+	cmd.PersistentFlags().Duration(RetryMaxDelayFlagName, kimsufi.DefaultRetryPolicy.MaxDelay, "maximum delay between retries, calls asked to retry after a longer delay are not retried")
+	cmd.PersistentFlags().Float64(RateLimitFlagName, kimsufi.DefaultRateLimit.Rate, "maximum number of OVH API calls per second per endpoint, 0 for no limit")
+	cmd.PersistentFlags().Int(RateBurstFlagName, kimsufi.DefaultRateLimit.Burst, "maximum number of OVH API calls made at once per endpoint")
+
+	// OVH API disk cache
+	BindCacheFlags(cmd)
+
+	// Configuration file and profile
+	BindConfigFlags(cmd)
+}
+
+// ApplyAPIPolicy sets the retry policy and rate limit of the OVH API calls from the flags of cmd.
+func ApplyAPIPolicy(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	retries, err := flags.GetInt(RetriesFlagName)
+	if err != nil {
+		return err
+	}
+	if retries < 0 {
+		return fmt.Errorf("--%s must not be negative", RetriesFlagName)
+	}
+
+	maxDelay, err := flags.GetDuration(RetryMaxDelayFlagName)
+	if err != nil {
+		return err
+	}
+
+	rate, err := flags.GetFloat64(RateLimitFlagName)
+	if err != nil {
+		return err
+	}
+
+	burst, err := flags.GetInt(RateBurstFlagName)
+	if err != nil {
+		return err
+	}
+	if burst < 1 {
+		return fmt.Errorf("--%s must be positive", RateBurstFlagName)
+	}
+
+	kimsufi.DefaultRetryPolicy.MaxAttempts = retries + 1
+	kimsufi.DefaultRetryPolicy.MaxDelay = maxDelay
+	kimsufi.SetRateLimit(kimsufi.RateLimit{Rate: rate, Burst: burst})
+
+	return nil
 }
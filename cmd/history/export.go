@@ -0,0 +1,83 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+)
+
+var (
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the availability history",
+		Long:  "Export the availability transitions recorded in the history file as CSV, JSON lines or Parquet",
+		Example: `  kimsufi-notifier history export --file history.parquet
+  kimsufi-notifier history export --plan-code 24ska01 --since 30d --format csv > history.csv`,
+		RunE: exportRunner,
+	}
+
+	// Flags variables
+	archiveFile   string
+	archiveFormat string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	exportCmd.Flags().StringVarP(&archiveFile, "file", "f", "-", "file to export to, - for stdout")
+	exportCmd.Flags().StringVar(&archiveFormat, "format", "", fmt.Sprintf("archive format, guessed from the file extension by default, jsonl for stdout (allowed values: %s)", strings.Join(history.ArchiveFormats, ", ")))
+
+	Cmd.AddCommand(exportCmd)
+}
+
+// exportRunner is the main function for the history export command
+func exportRunner(cmd *cobra.Command, args []string) error {
+	format, err := resolveArchiveFormat(archiveFormat, archiveFile)
+	if err != nil {
+		return err
+	}
+
+	transitions, err := LoadTransitions(cmd, historyFile, planCode, datacenters, since, until)
+	if err != nil {
+		return err
+	}
+
+	if archiveFile == "-" {
+		return history.Export(os.Stdout, format, transitions)
+	}
+
+	f, err := os.Create(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = history.Export(f, format, transitions)
+	if err != nil {
+		return fmt.Errorf("failed to export history: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d transitions to %s\n", len(transitions), archiveFile)
+
+	return nil
+}
+
+// resolveArchiveFormat returns format when set, otherwise guesses it from path.
+func resolveArchiveFormat(format, path string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+
+	if path == "-" {
+		return history.ArchiveFormatJSONL, nil
+	}
+
+	return history.ArchiveFormatFromPath(path)
+}
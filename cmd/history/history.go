@@ -0,0 +1,141 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "history",
+		Short: "List past availability windows",
+		Long:  "List past availability windows recorded in the history file\n\nhistory is recorded by running check with --record, and can be shared with the export and import subcommands",
+		Example: `  kimsufi-notifier history --plan-code 24ska01
+  kimsufi-notifier history --plan-code 24ska01 --datacenters gra,rbx --since 7d
+  kimsufi-notifier history --plan-code 24ska01 --output csv`,
+		RunE: runner,
+	}
+
+	// Flags variables
+	datacenters  []string
+	historyFile  string
+	outputFormat string
+	planCode     string
+	since        string
+	until        string
+	timezone     string
+)
+
+// init registers all flags
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &planCode)
+	flag.BindDatacentersFlag(Cmd, &datacenters)
+	flag.BindHistoryFileFlag(Cmd, &historyFile)
+	flag.BindOutputFlag(Cmd, &outputFormat)
+	flag.BindTimeRangeFlags(Cmd, &since, &until)
+	flag.BindTimezoneFlag(Cmd, &timezone)
+}
+
+// windowOutput is the structured output of a window.
+type windowOutput struct {
+	history.Window `json:",inline"`
+
+	Duration time.Duration `json:"duration"`
+	Open     bool          `json:"open"`
+}
+
+// runner is the main function for the history command
+func runner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.TimezoneFlagName, err)
+	}
+
+	transitions, err := LoadTransitions(cmd, historyFile, planCode, datacenters, since, until)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var (
+		headers = []string{"planCode", "datacenter", "start", "end", "duration"}
+		rows    [][]string
+		windows []windowOutput
+	)
+	for _, w := range transitions.Windows() {
+		end := "-"
+		if !w.IsOpen() {
+			end = w.End.In(loc).Format(time.DateTime)
+		}
+
+		duration := FormatDuration(w.Duration(now))
+		if w.IsOpen() {
+			duration += " (ongoing)"
+		}
+
+		rows = append(rows, []string{w.PlanCode, w.Datacenter, w.Start.In(loc).Format(time.DateTime), end, duration})
+		windows = append(windows, windowOutput{
+			Window:   w,
+			Duration: w.Duration(now),
+			Open:     w.IsOpen(),
+		})
+	}
+
+	return output.Write(os.Stdout, outputFormat, headers, rows, windows)
+}
+
+// LoadTransitions loads the transitions from the history file matching the given filters.
+// The endpoint is filtered on only when explicitly set on the command line.
+func LoadTransitions(cmd *cobra.Command, historyFile, planCode string, datacenters []string, since, until string) (history.Transitions, error) {
+	now := time.Now()
+
+	sinceTime, err := history.ParseTime(since, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flag.SinceFlagName, err)
+	}
+
+	untilTime, err := history.ParseTime(until, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flag.UntilFlagName, err)
+	}
+
+	filter := history.Filter{
+		PlanCode:    planCode,
+		Datacenters: datacenters,
+		Since:       sinceTime,
+		Until:       untilTime,
+	}
+
+	if flag.IsSet(cmd, flag.OVHAPIEndpointFlagName) {
+		filter.Endpoint = cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	}
+
+	store, err := history.NewStore(historyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	return transitions.Filter(filter), nil
+}
+
+// FormatDuration formats a duration rounded to the second.
+func FormatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+)
+
+var (
+	importCmd = &cobra.Command{
+		Use:   "import FILE...",
+		Short: "Import availability history",
+		Long:  "Import availability transitions from CSV, JSON lines or Parquet archives into the history file\n\nobservations already known are deduplicated, so history from several collectors can be merged",
+		Example: `  kimsufi-notifier history import collector-1.parquet collector-2.csv
+  kimsufi-notifier history import --plan-code 24ska01 --since 30d history.jsonl
+  kimsufi-notifier history import --endpoint ovh-ca collector-1.parquet
+  cat history.jsonl | kimsufi-notifier history import -`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: importRunner,
+	}
+
+	// Flags variables
+	importFormat string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", fmt.Sprintf("archive format, guessed from the file extension by default, jsonl for stdin (allowed values: %s)", strings.Join(history.ArchiveFormats, ", ")))
+
+	Cmd.AddCommand(importCmd)
+}
+
+// importRunner is the main function for the history import command
+func importRunner(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	sinceTime, err := history.ParseTime(since, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.SinceFlagName, err)
+	}
+	untilTime, err := history.ParseTime(until, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.UntilFlagName, err)
+	}
+
+	filter := history.Filter{
+		PlanCode:    planCode,
+		Datacenters: datacenters,
+		Since:       sinceTime,
+		Until:       untilTime,
+	}
+
+	// Like export, the endpoint is filtered on only when explicitly set.
+	if flag.IsSet(cmd, flag.OVHAPIEndpointFlagName) {
+		filter.Endpoint = cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	}
+
+	var transitions history.Transitions
+	for _, path := range args {
+		t, err := readArchive(path)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", path, err)
+		}
+		transitions = append(transitions, t.Filter(filter)...)
+	}
+
+	store, err := history.NewStore(historyFile)
+	if err != nil {
+		return err
+	}
+
+	before, after, err := store.Import(transitions)
+	if err != nil {
+		return fmt.Errorf("failed to import history: %w", err)
+	}
+
+	fmt.Printf("read %d transitions, history grew from %d to %d transitions\n", len(transitions), before, after)
+
+	return nil
+}
+
+// readArchive reads the transitions of the archive at path, - reads from stdin.
+func readArchive(path string) (history.Transitions, error) {
+	format, err := resolveArchiveFormat(importFormat, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	return history.Import(r, format)
+}
@@ -0,0 +1,166 @@
+package list
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	pkgcategory "github.com/TheoBrigitte/kimsufi-notifier/pkg/category"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+// regionPlan is a plan sold in a subsidiary.
+type regionPlan struct {
+	kimsufi.Target
+	PlanCode    string
+	Category    string
+	Name        string
+	Price       float64
+	Currency    string
+	Status      string
+	Datacenters []string
+}
+
+// runnerAllRegions lists the servers of every endpoint and country, merged by plan and sorted by price.
+func runnerAllRegions(cmd *cobra.Command) error {
+	if category == "vps" {
+		return fmt.Errorf("--all-regions is not supported with --category vps")
+	}
+
+	m, err := kimsufi.NewMultiService(log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	m = m.WithContext(cmd.Context())
+
+	// Catalogs differ per subsidiary, availabilities only per endpoint.
+	catalogs := kimsufi.FanOut(m, m.Targets(), parallelism, func(k *kimsufi.Service, t kimsufi.Target) (*kimsuficatalog.Catalog, error) {
+		return k.ListServers(t.Subsidiary)
+	})
+	availabilities := kimsufi.FanOut(m, m.EndpointTargets(), parallelism, func(k *kimsufi.Service, t kimsufi.Target) (*kimsufiavailability.Availabilities, error) {
+		a, err := k.GetAvailabilities(datacenters, planCode, nil)
+		if kimsufi.IsAvailabilityNotFoundError(err) {
+			return &kimsufiavailability.Availabilities{}, nil
+		}
+		return a, err
+	})
+
+	availabilitiesByEndpoint := make(map[string]*kimsufiavailability.Availabilities)
+	for _, r := range availabilities {
+		if r.Err != nil {
+			log.Warnf("failed to list availabilities in %s: %v", r.Endpoint, r.Err)
+			continue
+		}
+		availabilitiesByEndpoint[r.Endpoint] = r.Value
+	}
+
+	var plans []regionPlan
+	var errs []error
+	for _, r := range catalogs {
+		if r.Err != nil {
+			log.Warnf("failed to list servers in %s/%s: %v", r.Endpoint, r.Subsidiary, r.Err)
+			errs = append(errs, r.Err)
+			continue
+		}
+
+		plans = append(plans, regionPlans(r.Target, r.Value, availabilitiesByEndpoint[r.Endpoint])...)
+	}
+	if len(errs) == len(catalogs) {
+		return fmt.Errorf("failed to list servers: %w", errors.Join(errs...))
+	}
+
+	// Sort plans by category, plan code, currency, then price, prices of different currencies are not comparable.
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].Category != plans[j].Category {
+			return plans[i].Category < plans[j].Category
+		}
+		if plans[i].PlanCode != plans[j].PlanCode {
+			return plans[i].PlanCode < plans[j].PlanCode
+		}
+		if plans[i].Currency != plans[j].Currency {
+			return plans[i].Currency < plans[j].Currency
+		}
+
+		return plans[i].Price < plans[j].Price
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "planCode\tcategory\tname\tendpoint\tcountry\tprice\tstatus\tdatacenters")
+	fmt.Fprintln(w, "--------\t--------\t----\t--------\t-------\t-----\t------\t-----------")
+
+	nothingAvailable := true
+	for _, p := range plans {
+		if p.Status == kimsufiavailability.StatusAvailable {
+			nothingAvailable = false
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.2f %s\t%s\t%s\n", p.PlanCode, p.Category, p.Name, p.Endpoint, p.Subsidiary, p.Price, p.Currency, p.Status, strings.Join(p.Datacenters, ", "))
+	}
+	w.Flush()
+
+	if nothingAvailable {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// regionPlans returns the plans of the catalog sold in the target subsidiary, filtered by the flags,
+// with their availability in the target endpoint.
+func regionPlans(target kimsufi.Target, catalog *kimsuficatalog.Catalog, availabilities *kimsufiavailability.Availabilities) []regionPlan {
+	var plans []regionPlan
+	for _, plan := range catalog.Plans {
+		if planCode != "" && plan.PlanCode != planCode {
+			continue
+		}
+
+		planCategory := plan.GetCategory()
+		if category != "" && category != planCategory {
+			continue
+		}
+
+		var price float64
+		planPrice := plan.GetFirstPrice()
+		if !reflect.DeepEqual(planPrice, kimsuficatalog.PlanPricing{}) {
+			price = planPrice.GetPrice()
+		}
+
+		categoryDisplay := pkgcategory.GetDisplayName(planCategory)
+		if categoryDisplay == "" {
+			categoryDisplay = planCategory
+		}
+
+		p := regionPlan{
+			Target:   target,
+			PlanCode: plan.PlanCode,
+			Category: categoryDisplay,
+			Name:     plan.InvoiceName,
+			Price:    price,
+			Currency: catalog.Locale.CurrencyCode,
+			Status:   "unknown",
+		}
+
+		if availabilities != nil {
+			datacenters := availabilities.GetByPlanCode(plan.PlanCode).GetAvailableDatacenters()
+			p.Status = datacenters.Status()
+			if humanLevel > 0 {
+				p.Datacenters = datacenters.ToFullNamesOrCodes()
+			} else {
+				p.Datacenters = datacenters.Codes()
+			}
+		}
+
+		plans = append(plans, p)
+	}
+
+	return plans
+}
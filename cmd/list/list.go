@@ -26,14 +26,17 @@ var (
 		Long:  "List servers from OVH Eco (including Kimsufi) catalog and VPS catalog",
 		Example: `  kimsufi-notifier list --category kimsufi
   kimsufi-notifier list --category vps --country FR
-  kimsufi-notifier list --country US --endpoint ovh-us`,
+  kimsufi-notifier list --country US --endpoint ovh-us
+  kimsufi-notifier list --plan-code 24ska01 --all-regions`,
 		RunE: runner,
 	}
 
 	// Flags variables
+	allRegions  bool
 	category    string
 	datacenters []string
 	humanLevel  int
+	parallelism int
 	planCode    string
 )
 
@@ -42,18 +45,28 @@ func init() {
 	flag.BindCategoryFlag(Cmd, &category)
 	flag.BindDatacentersFlag(Cmd, &datacenters)
 	flag.BindHumanFlag(Cmd, &humanLevel)
+	flag.BindAllRegionsFlags(Cmd, &allRegions, &parallelism)
 
 	Cmd.PersistentFlags().StringVarP(&planCode, flag.PlanCodeFlagName, flag.PlanCodeFlagShortName, "", fmt.Sprintf("plan code to filter on (e.g. %s)", flag.PlanCodeExample))
 }
 
 // runner is the main function for the list command
 func runner(cmd *cobra.Command, args []string) error {
+	if allRegions {
+		err := flag.CheckAllRegionsFlags(cmd)
+		if err != nil {
+			return err
+		}
+		return runnerAllRegions(cmd)
+	}
+
 	// Initialize kimsufi service
 	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
 	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
+	k = k.WithContext(cmd.Context())
 
 	// Check if we're requesting VPS specifically
 	if category == "vps" {
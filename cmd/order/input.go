@@ -2,7 +2,12 @@ package order
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"golang.org/x/term"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/credentials"
 	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
 )
 
@@ -10,40 +15,13 @@ const (
 	maxInputRetries = 3
 )
 
-// generateItemManualConfiguration asks the user to select a value for each required configuration not already set in mergedConfigs
-func generateItemManualConfiguration(mergedConfigs kimsufiorder.ItemConfigurationRequests, requiredConfigs []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
+// generateItemManualConfiguration asks the user to select a value for each of the missing required configurations
+func generateItemManualConfiguration(missingConfigs []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
 	var manualConfigs kimsufiorder.ItemConfigurationRequests
-	for _, option := range requiredConfigs {
-		if !option.Required {
-			continue
-		}
-		i := mergedConfigs.GetByLabel(option.Label)
-		if i != nil {
-			continue
-		}
-
-		fmt.Printf("> cart item manual configuration, select a value for %s\n", option.Label)
-		for index, value := range option.AllowedValues {
-			fmt.Printf("  %d. %s\n", index, value)
-		}
-		var choice int
-		var err error
-		for i := 0; i < maxInputRetries; i++ {
-			fmt.Printf("> Choice: ")
-			_, err = fmt.Scan(&choice)
-			if err != nil {
-				fmt.Printf("  invalid choice: %v\n", err)
-			} else if choice < 0 || choice >= len(option.AllowedValues) {
-				fmt.Printf("  invalid choice: %d\n", choice)
-			} else {
-				break
-			}
-		}
+	for _, option := range missingConfigs {
+		choice, err := choose(fmt.Sprintf("cart item manual configuration, select a value for %s", option.Label), option.AllowedValues)
 		if err != nil {
-			return nil, fmt.Errorf("too many invalid choices: %w", err)
-		}
-		if choice < 0 || choice >= len(option.AllowedValues) {
-			return nil, fmt.Errorf("invalid choice: %d", choice)
+			return nil, err
 		}
 
 		m := kimsufiorder.ItemConfigurationRequest{
@@ -55,3 +33,86 @@ func generateItemManualConfiguration(mergedConfigs kimsufiorder.ItemConfiguratio
 
 	return manualConfigs, nil
 }
+
+// choose asks the user to select one of the values and returns its index.
+func choose(title string, values []string) (int, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no value to select for %s", title)
+	}
+
+	fmt.Printf("> %s\n", title)
+	for index, value := range values {
+		fmt.Printf("  %d. %s\n", index, value)
+	}
+
+	var choice int
+	var err error
+	for i := 0; i < maxInputRetries; i++ {
+		fmt.Printf("> Choice: ")
+		_, err = fmt.Scan(&choice)
+		if err != nil {
+			fmt.Printf("  invalid choice: %v\n", err)
+		} else if choice < 0 || choice >= len(values) {
+			fmt.Printf("  invalid choice: %d\n", choice)
+		} else {
+			break
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("too many invalid choices: %w", err)
+	}
+	if choice < 0 || choice >= len(values) {
+		return 0, fmt.Errorf("invalid choice: %d", choice)
+	}
+
+	return choice, nil
+}
+
+// confirm asks the user a yes or no question, anything but yes is a no.
+func confirm(question string) (bool, error) {
+	fmt.Printf("> %s [y/n]: ", question)
+
+	var answer string
+	_, err := fmt.Scan(&answer)
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+// readPassphrase returns the credentials file passphrase from its environment variable,
+// or asks it in the terminal, twice when the file is created.
+func readPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv(credentials.PassphraseEnvVarName); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%s env var is required when not running in a terminal", credentials.PassphraseEnvVarName)
+	}
+
+	fmt.Print("> Credentials passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+
+	if create {
+		fmt.Print("> Confirm passphrase: ")
+		confirmation, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		if string(confirmation) != string(passphrase) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return string(passphrase), nil
+}
@@ -9,31 +9,16 @@ import (
 	"time"
 
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/credentials"
 	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
 	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
 	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
-	kimsufiregion "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/region"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderflow"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
-const (
-	anyOption = "any"
-)
-
-// mapVPSOptionFamily maps user-friendly option names to API option families for VPS
-func mapVPSOptionFamily(userFamily string) string {
-	switch userFamily {
-	case "os":
-		return "vps_os"
-	case "backup":
-		return "vps_backupid"
-	default:
-		return userFamily
-	}
-}
-
 var (
 	Cmd = &cobra.Command{
 		Use:   "order",
@@ -42,316 +27,431 @@ var (
 		Example: `  kimsufi-notifier order --plan-code 24ska01 --datacenter rbx --dry-run
   kimsufi-notifier order --plan-code 25skle01 --datacenter bhs --item-option memory=ram-32g-noecc-1333-25skle01,storage=softraid-3x2000sa-25skle01
   kimsufi-notifier order --plan-code vps-starter-1-2-20 --datacenter GRA --dry-run
-  kimsufi-notifier order --plan-code vps-2025-model2 --datacenter US-WEST-OR --item-option os=option-linux`,
+  kimsufi-notifier order --plan-code vps-2025-model2 --datacenter US-WEST-OR --item-option os=option-linux
+  kimsufi-notifier order --plan-code 24ska01 --datacenters rbx --preview
+  kimsufi-notifier order --plan-code 24ska01 --datacenters any --parallel --max-orders 1
+  kimsufi-notifier order --candidate plan=24sk50,datacenters=rbx+gra+sbg --candidate plan=24sk60,datacenters=rbx+gra+sbg --total-quantity 1`,
 		RunE: runner,
 	}
 
 	// Flags variables
-	autoPay     bool
-	datacenters []string
-	planCode    string
-	quantity    int
+	followInterval time.Duration
+	followOrder    bool
+	interactive    bool
+	params         Params
+	specFile       string
+	webhooks       []string
+)
 
-	itemUserConfigurations map[string]string
-	itemUserOptions        []string
+// Params holds the order flags.
+type Params struct {
+	orderflow.Options
+
+	ListConfigurations bool
+	ListOptions        bool
+	ListPrices         bool
+	// Preview prints the checkout preview instead of placing the order.
+	Preview bool
+
+	// Parallel races one cart per datacenter, placing at most MaxOrders orders.
+	Parallel  bool
+	MaxOrders int
+
+	// Candidates are plans accepted in order of preference, see orderflow.ParseCandidate.
+	Candidates []string
+	// TotalQuantity is the item quantity ordered across candidates.
+	TotalQuantity int
+
+	// BudgetLimits are set in Options.Budget when any limit is set, see OrderOptions.
+	BudgetLimits orderflow.Budget
+
+	// Names of the environment variables holding the OVH API credentials.
+	AppKeyEnvVarName      string
+	AppSecretEnvVarName   string
+	ConsumerKeyEnvVarName string
+	// CredentialsFile holds the encrypted credentials saved by auth login, used when the env vars are not set.
+	CredentialsFile string
+}
 
-	listConfigurations bool
-	listOptions        bool
-	listPrices         bool
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &params.PlanCode)
+	BindFlags(Cmd, &params)
 
-	priceDuration string
-	priceMode     string
+	Cmd.PersistentFlags().StringSliceVarP(&params.Datacenters, "datacenters", "d", nil, fmt.Sprintf(`datacenters, comma separated list, %q to try all datacenters (known values: %s)`, orderflow.AnyDatacenter, strings.Join(kimsufiavailability.GetDatacentersKnownCodes(), ", ")))
 
-	ovhAppKeyEnvVarName      string
-	ovhAppSecretEnvVarName   string
-	ovhConsumerKeyEnvVarName string
+	Cmd.PersistentFlags().BoolVar(&params.ListConfigurations, "list-configurations", false, "list available item configurations")
+	Cmd.PersistentFlags().BoolVar(&params.ListOptions, "list-options", false, "list available item options")
+	Cmd.PersistentFlags().BoolVar(&params.ListPrices, "list-prices", false, "list available prices")
 
-	dryRun bool
-)
+	Cmd.PersistentFlags().BoolVar(&interactive, "interactive", false, "walk through the order choices in the terminal, ending on a summary to confirm before checkout")
+	Cmd.PersistentFlags().StringVar(&specFile, "spec", "", "YAML order spec file, validated against the catalog, flags set on the command line take precedence over its values")
 
-func init() {
-	flag.BindPlanCodeFlag(Cmd, &planCode)
+	Cmd.PersistentFlags().BoolVar(&followOrder, "follow", false, "follow the placed orders until they are delivered, see order status")
+	Cmd.PersistentFlags().DurationVar(&followInterval, "follow-interval", time.Minute, "time between order status checks with --follow")
+	flag.BindNotifyWebhookFlag(Cmd, &webhooks)
 
-	Cmd.PersistentFlags().BoolVar(&autoPay, "auto-pay", false, "automatically pay the order")
-	Cmd.PersistentFlags().StringSliceVarP(&datacenters, "datacenters", "d", nil, fmt.Sprintf(`datacenters, comma separated list, %q to try all datacenters (known values: %s)`, anyOption, strings.Join(kimsufiavailability.GetDatacentersKnownCodes(), ", ")))
-	Cmd.PersistentFlags().IntVarP(&quantity, "quantity", "q", kimsufiorder.QuantityDefault, "item quantity")
+	Cmd.PersistentFlags().BoolVar(&params.Preview, "preview", false, "prepare and assign the cart, then print the itemised bill it would be checked out with, without placing the order")
+	Cmd.PersistentFlags().BoolVar(&params.Parallel, "parallel", false, "prepare one cart per datacenter concurrently instead of trying datacenters one after the other, at most --max-orders checkouts are in flight at once so they are serialised with the default --max-orders 1")
+	Cmd.PersistentFlags().IntVar(&params.MaxOrders, "max-orders", 1, "maximum number of orders placed with --parallel, only allowed with --parallel")
+	Cmd.PersistentFlags().IntVar(&params.TotalQuantity, "total-quantity", 1, "total item quantity ordered across --candidate plans, the last order is of the remaining items only")
+}
 
-	Cmd.PersistentFlags().StringToStringVarP(&itemUserConfigurations, "item-configuration", "i", nil, "item configuration, comma separated list, see --list-configurations for available values (e.g. region=europe)")
-	Cmd.PersistentFlags().StringSliceVarP(&itemUserOptions, "item-option", "o", nil, fmt.Sprintf("item option, comma separated list, use any to include all options, see --list-options for available values (e.g. memory=ram-64g-noecc-2133-24ska01, os=option-linux for VPS, memory=%[1]s, %[1]s)", anyOption))
+// BindFlags binds the order flags shared with the commands placing orders to the provided cmd and p.
+func BindFlags(cmd *cobra.Command, p *Params) {
+	cmd.PersistentFlags().BoolVar(&p.AutoPay, "auto-pay", false, "automatically pay the order")
+	cmd.PersistentFlags().IntVarP(&p.Quantity, "quantity", "q", kimsufiorder.QuantityDefault, "item quantity")
 
-	Cmd.PersistentFlags().BoolVar(&listConfigurations, "list-configurations", false, "list available item configurations")
-	Cmd.PersistentFlags().BoolVar(&listOptions, "list-options", false, "list available item options")
-	Cmd.PersistentFlags().BoolVar(&listPrices, "list-prices", false, "list available prices")
+	cmd.PersistentFlags().StringToStringVarP(&p.ItemConfigurations, "item-configuration", "i", nil, "item configuration, comma separated list, see --list-configurations for available values (e.g. region=europe)")
+	cmd.PersistentFlags().StringSliceVarP(&p.ItemOptions, "item-option", "o", nil, fmt.Sprintf("item option, comma separated list, use any to include all options, see --list-options for available values (e.g. memory=ram-64g-noecc-2133-24ska01, os=option-linux for VPS, memory=%[1]s, %[1]s)", orderflow.AnyOption))
 
-	Cmd.PersistentFlags().StringVar(&priceMode, "price-mode", kimsufiorder.PricingMode, "price mode, see --list-prices for available values")
-	Cmd.PersistentFlags().StringVar(&priceDuration, "price-duration", kimsufiorder.PriceDuration, "price duration, see --list-prices for available values")
+	cmd.PersistentFlags().StringVar(&p.PriceMode, "price-mode", kimsufiorder.PricingMode, "price mode, see --list-prices for available values")
+	cmd.PersistentFlags().StringVar(&p.PriceDuration, "price-duration", kimsufiorder.PriceDuration, "price duration, see --list-prices for available values")
 
-	Cmd.PersistentFlags().StringVar(&ovhAppKeyEnvVarName, "ovh-app-key", "OVH_APP_KEY", "environement variable name for OVH API application key")
-	Cmd.PersistentFlags().StringVar(&ovhAppSecretEnvVarName, "ovh-app-secret", "OVH_APP_SECRET", "environement variable name for OVH API application secret")
-	Cmd.PersistentFlags().StringVar(&ovhConsumerKeyEnvVarName, "ovh-consumer-key", "OVH_CONSUMER_KEY", "environement variable name for OVH API consumer key")
+	BindCredentialsFlags(cmd, p)
 
-	Cmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "n", false, "only create a cart and do not submit the order")
+	cmd.PersistentFlags().BoolVarP(&p.DryRun, "dry-run", "n", false, "only create a cart and do not submit the order")
+
+	cmd.PersistentFlags().Float64Var(&p.BudgetLimits.MaxMonthly, "max-monthly-price", 0, "maximum price per month of one server without tax, the ordered duration price is divided by its months and by the quantity, the order is aborted when the cart exceeds it")
+	cmd.PersistentFlags().Float64Var(&p.BudgetLimits.MaxSetup, "max-setup-fee", 0, "maximum setup fee without tax, the order is aborted when the cart exceeds it")
+	cmd.PersistentFlags().Float64Var(&p.BudgetLimits.MaxTotal, "max-total-price", 0, "maximum total price with tax, the order is aborted when the cart exceeds it")
+	cmd.PersistentFlags().StringSliceVar(&p.BudgetLimits.Currencies, "currency", nil, "allowed currencies, comma separated list, the order is aborted when the cart is priced in another currency (e.g. EUR)")
+
+	cmd.PersistentFlags().StringArrayVar(&p.Candidates, "candidate", nil, "plan accepted instead of --plan-code, repeat in order of preference, as comma separated fields plan, datacenters and options with + separated values (e.g. plan=24sk50,datacenters=rbx+gra,options=memory=ram-32g-ecc-2133-24sk50)")
 }
 
-func runner(cmd *cobra.Command, args []string) error {
-	ovhSubsidiary := cmd.Flag(flag.CountryFlagName).Value.String()
+// BindCredentialsFlags binds the flags naming the OVH API credentials environment variables to the provided cmd and p.
+func BindCredentialsFlags(cmd *cobra.Command, p *Params) {
+	cmd.PersistentFlags().StringVar(&p.AppKeyEnvVarName, "ovh-app-key", "OVH_APP_KEY", "environement variable name for OVH API application key")
+	cmd.PersistentFlags().StringVar(&p.AppSecretEnvVarName, "ovh-app-secret", "OVH_APP_SECRET", "environement variable name for OVH API application secret")
+	cmd.PersistentFlags().StringVar(&p.ConsumerKeyEnvVarName, "ovh-consumer-key", "OVH_CONSUMER_KEY", "environement variable name for OVH API consumer key")
+	cmd.PersistentFlags().StringVar(&p.CredentialsFile, "credentials-file", "", fmt.Sprintf("encrypted file holding the credentials saved by auth login, used when the env vars are not set, its passphrase is read from %s or asked in the terminal (default $XDG_CONFIG_HOME/kimsufi-notifier/%s)", credentials.PassphraseEnvVarName, credentials.FileName))
+}
 
-	// Validate command arguments
-	if planCode == "" {
-		return fmt.Errorf("--plan-code is required")
-	}
-	if ovhSubsidiary == "" {
-		return fmt.Errorf("--country is required")
+// OrderOptions returns the order options, with the budget when any limit is set.
+func (p Params) OrderOptions() orderflow.Options {
+	o := p.Options
+	if !p.BudgetLimits.IsZero() {
+		b := p.BudgetLimits
+		o.Budget = &b
 	}
 
-	// Initialize kimsufi service
-	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
-	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
+	return o
+}
 
-	// Check if this is a VPS plan code and route to VPS ordering
-	isVPSPlan := strings.HasPrefix(planCode, "vps-") || strings.HasPrefix(planCode, "s1-")
-	if isVPSPlan {
-		return runnerVPS(cmd, k, ovhSubsidiary)
+// ReadCredentials reads the OVH API credentials from the environment variables named in p.
+// Credentials missing from the environment are read from the credentials saved by auth login for p.Endpoint,
+// unless the application key set in the environment is not the saved one.
+func ReadCredentials(p Params) (*orderflow.Credentials, error) {
+	var store *credentials.Store
+	if (os.Getenv(p.AppKeyEnvVarName) == "" || os.Getenv(p.AppSecretEnvVarName) == "" || os.Getenv(p.ConsumerKeyEnvVarName) == "") && p.Endpoint != "" {
+		var err error
+		store, err = CredentialsStore(p)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Create cart
-	expire := time.Now().AddDate(0, 0, 1)
-	cart, err := k.CreateCart(ovhSubsidiary, expire)
+	c, err := ReadApplication(p, store)
 	if err != nil {
-		return fmt.Errorf("error: %w", err)
+		return nil, err
 	}
-	fmt.Printf("> cart created id=%s\n", cart.CartID)
 
-	// Retrieve item options
-	ecoOptions, err := k.GetEcoOptions(cart.CartID, planCode)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
+	if c.ConsumerKey == "" {
+		return nil, fmt.Errorf("%s env var is required, or save a consumer key with auth login", p.ConsumerKeyEnvVarName)
 	}
 
-	priceConfig := kimsufiorder.EcoItemPriceConfig{
-		Duration:    priceDuration,
-		PricingMode: priceMode,
-	}
+	return c, nil
+}
 
-	if listOptions {
-		printItemOptions(ecoOptions, priceConfig)
-		return nil
+// ReadApplication reads the OVH API credentials like ReadCredentials from the given store, which may be nil,
+// only the application key and secret are required.
+func ReadApplication(p Params, store *credentials.Store) (*orderflow.Credentials, error) {
+	c := &orderflow.Credentials{
+		AppKey:      os.Getenv(p.AppKeyEnvVarName),
+		AppSecret:   os.Getenv(p.AppSecretEnvVarName),
+		ConsumerKey: os.Getenv(p.ConsumerKeyEnvVarName),
 	}
 
-	// Retrieve item informations
-	ecoInfo, err := k.GetEcoInfo(cart.CartID, planCode)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
+	if (c.AppKey == "" || c.AppSecret == "" || c.ConsumerKey == "") && store != nil && p.Endpoint != "" {
+		saved, err := store.Get(p.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saved credentials: %w", err)
+		}
+
+		// The secret and consumer key are only valid for the application they were saved with.
+		if saved != nil && (c.AppKey == "" || c.AppKey == saved.AppKey) {
+			c.AppKey = saved.AppKey
+			if c.AppSecret == "" {
+				c.AppSecret = saved.AppSecret
+			}
+			if c.ConsumerKey == "" {
+				c.ConsumerKey = saved.ConsumerKey
+			}
+		}
 	}
 
-	if listPrices {
-		return printPrices(ecoInfo, planCode)
+	if c.AppKey == "" {
+		return nil, fmt.Errorf("%s env var is required, or save credentials with auth login", p.AppKeyEnvVarName)
 	}
+	if c.AppSecret == "" {
+		return nil, fmt.Errorf("%s env var is required, or save credentials with auth login", p.AppSecretEnvVarName)
+	}
+
+	return c, nil
+}
 
-	// Ensure price config is valid, otherwise use default
-	priceConfig = ecoInfo.GetPriceConfigOrDefault(planCode, priceConfig)
+// CredentialsStore returns the store of the credentials saved by auth login, at p.CredentialsFile.
+// Its passphrase is read from the environment, or asked in the terminal.
+func CredentialsStore(p Params) (*credentials.Store, error) {
+	return credentials.NewStore(p.CredentialsFile, readPassphrase)
+}
 
-	// Add plan to cart
-	item, err := k.AddEcoItem(cart.CartID, planCode, quantity, priceConfig)
+// Authenticate returns a copy of k authenticated with the OVH API credentials read from the environment variables named in p.
+func Authenticate(k *kimsufi.Service, p Params) (*kimsufi.Service, error) {
+	credentials, err := ReadCredentials(p)
 	if err != nil {
-		return fmt.Errorf("error: %w", err)
+		return nil, err
 	}
-	fmt.Printf("> cart item added id=%d\n", item.ItemID)
 
-	requiredConfigurations, err := k.GetItemRequiredConfiguration(cart.CartID, item.ItemID)
+	k, err = k.WithAuth(credentials.AppKey, credentials.AppSecret, credentials.ConsumerKey)
 	if err != nil {
-		return fmt.Errorf("error: %w", err)
+		return nil, fmt.Errorf("error: %w", err)
 	}
 
-	if listConfigurations {
-		printConfigurations(requiredConfigurations)
-		return nil
+	return k, nil
+}
+
+// Progress prints the order progress.
+func Progress(e orderflow.Event) {
+	switch e.Step {
+	case orderflow.StepCartCreated:
+		fmt.Printf("> cart created id=%s\n", e.CartID)
+	case orderflow.StepItemAdded:
+		fmt.Printf("> cart item added id=%d\n", e.ItemID)
+	case orderflow.StepDatacentersResolved:
+		fmt.Printf("> using datacenters: %s\n", strings.Join(e.Datacenters, ", "))
+	case orderflow.StepItemConfigured:
+		fmt.Printf("> cart item configured: %s=%s\n", e.Label, e.Value)
+	case orderflow.StepReady:
+		fmt.Printf("> item options: %d %v\n", len(e.Options), e.Options)
+		fmt.Printf("> datacenter(s): %d\n", len(e.Datacenters))
+		fmt.Printf("> combinations: %d\n", e.Combinations)
+	case orderflow.StepDryRun:
+		fmt.Println("> dry-run enabled, skipping order submission")
+	case orderflow.StepCartAssigned:
+		fmt.Println("> cart assigned")
+	case orderflow.StepCartRefreshed:
+		fmt.Printf("> cart %s expiry refreshed until %s\n", e.CartID, e.Expire.Local().Format(time.DateTime))
+	case orderflow.StepOptionConfigured:
+		fmt.Printf("> cart option set: %s=%s\n", e.Label, e.Value)
+	case orderflow.StepDatacenterConfigured:
+		fmt.Printf("> datacenter %s configured\n", e.Datacenter)
+	case orderflow.StepBudgetChecked:
+		fmt.Printf("> prices within budget: %s\n", e.Prices)
+	case orderflow.StepDatacenterUnavailable:
+		fmt.Printf("> datacenter %s not available\n", e.Datacenter)
+	case orderflow.StepCheckoutFailed:
+		fmt.Printf("> error: %v\n", e.Err)
+	case orderflow.StepOrderCompleted:
+		fmt.Printf("> order completed: %s\n", e.URL)
+	case orderflow.StepCandidateEvaluated:
+		fmt.Printf("> candidate %s\n", e.Reason)
+	case orderflow.StepCandidateSelected:
+		fmt.Printf("> %s\n", e.Reason)
 	}
+}
 
-	if len(datacenters) == 0 {
-		return fmt.Errorf("--datacenter is required")
-	} else if slices.Contains(datacenters, anyOption) {
-		catalog, err := k.ListServers(cmd.Flag(flag.CountryFlagName).Value.String())
+func runner(cmd *cobra.Command, args []string) error {
+	params.Endpoint = cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	params.Subsidiary = cmd.Flag(flag.CountryFlagName).Value.String()
+	params.Configure = generateItemManualConfiguration
+	params.Progress = Progress
+
+	var spec *orderflow.Spec
+	if specFile != "" {
+		var err error
+		spec, err = orderflow.LoadSpec(specFile)
 		if err != nil {
-			return fmt.Errorf("failed to list servers: %w", err)
-		}
-
-		plan := catalog.GetPlan(planCode)
-		if plan == nil {
-			return fmt.Errorf("plan %s not found", planCode)
-		}
-
-		datacenterConfiguration := plan.GetConfiguration(kimsufiorder.ConfigurationLabelDatacenter)
-		if datacenterConfiguration == nil {
-			return fmt.Errorf("datacenter configuration not found")
+			return err
 		}
+		applySpec(cmd, spec)
+	}
 
-		datacenters = datacenterConfiguration.Values
+	candidates, err := orderflow.ParseCandidates(params.Candidates)
+	if err != nil {
+		return err
+	}
+	if spec != nil && len(candidates) > 0 {
+		return fmt.Errorf("--spec and --candidate can not be used together")
 	}
 
-	// Prepare item configurations
-	itemConfigurations := kimsufiorder.NewItemConfigurationsFromMap(itemUserConfigurations)
-	r := kimsufiregion.GetRegionFromEndpoint(endpoint)
-	if r != nil {
-		itemConfigurations.Add(kimsufiorder.ConfigurationLabelRegion, r.Region)
+	// Validate command arguments
+	if params.PlanCode == "" && len(candidates) == 0 && !interactive {
+		return fmt.Errorf("--plan-code or --candidate is required")
+	}
+	if params.PlanCode != "" && len(candidates) > 0 {
+		return fmt.Errorf("--plan-code and --candidate can not be used together")
+	}
+	if interactive && len(candidates) > 0 {
+		return fmt.Errorf("--interactive can not be used with --candidate")
+	}
+	if params.Parallel && len(candidates) > 0 {
+		return fmt.Errorf("--parallel can not be used with --candidate")
+	}
+	if flag.IsSet(cmd, "total-quantity") && len(candidates) == 0 {
+		return fmt.Errorf("--total-quantity requires --candidate")
+	}
+	if params.TotalQuantity < 1 {
+		return fmt.Errorf("--total-quantity must be positive")
+	}
+	if flag.IsSet(cmd, "max-orders") && !params.Parallel {
+		return fmt.Errorf("--max-orders requires --parallel")
+	}
+	if params.MaxOrders < 1 {
+		return fmt.Errorf("--max-orders must be positive")
+	}
+	if params.Subsidiary == "" {
+		return fmt.Errorf("--country is required")
 	}
 
-	autoConfigs := k.GenerateItemAutoConfigurations(requiredConfigurations)
-	userConfigs := autoConfigs.Merge(itemConfigurations)
-	manualConfigs, err := generateItemManualConfiguration(userConfigs, requiredConfigurations)
+	// Initialize kimsufi service
+	k, err := kimsufi.NewService(params.Endpoint, log.StandardLogger(), nil)
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
-	configurations := userConfigs.Merge(manualConfigs)
+	k = k.WithContext(cmd.Context())
 
-	// Configure item
-	for _, configuration := range configurations {
-		resp, err := k.AddItemConfiguration(cart.CartID, item.ItemID, configuration)
+	// Validate the spec once overridden by the flags, as it will be ordered
+	if spec != nil {
+		err = orderflow.ValidateOptions(k, params.Options)
 		if err != nil {
-			return fmt.Errorf("error: %w", err)
+			return fmt.Errorf("%s: %w", specFile, err)
 		}
-		fmt.Printf("> cart item configured: %s=%s\n", resp.Label, resp.Value)
-	}
-
-	// Prepare item options
-	var optionsCombinations []kimsufiorder.Options
-	var mergedOptions kimsufiorder.Options
-	allOptions := slices.Contains(itemUserOptions, anyOption)
-	if allOptions {
-		// Get all mandatory options
-		mergedOptions = ecoOptions.GetMandatoryOptions(nil).ToOptions()
-		optionsCombinations = kimsufiorder.NewOptionsCombinationsFromSlice(mergedOptions)
-	} else {
-		userOptions, err := kimsufiorder.NewOptionsFromSlice(itemUserOptions)
+	}
+
+	if params.ListConfigurations || params.ListOptions || params.ListPrices {
+		if len(candidates) > 0 {
+			return fmt.Errorf("--list-configurations, --list-options and --list-prices require --plan-code")
+		}
+		return list(k, params)
+	}
+
+	if interactive {
+		confirmed, err := wizard(k, &params)
 		if err != nil {
 			return fmt.Errorf("error: %w", err)
 		}
-		anyOptions, userOptions := userOptions.SplitByPlanCode(anyOption)
-		anyFamilies := anyOptions.Families()
-		userFamilies := userOptions.Families()
-
-		optionFilter := func(opts kimsufiorder.EcoItemOptions, o kimsufiorder.EcoItemOption) bool {
-			defautPriceConfig := kimsufiorder.EcoItemPriceConfig{
-				Duration:    kimsufiorder.PriceDuration,
-				PricingMode: kimsufiorder.PricingMode,
-			}
-
-			// Inclue option if it is marked as any
-			if slices.Contains(anyFamilies, o.Family) {
-				return true
-			}
-
-			if !slices.Contains(userFamilies, o.Family) {
-				return true
-			}
-
-			// Include option if its family is not already included
-			current := opts.Get(o.Family)
-			if current == nil {
-				return true
-			}
-
-			newPrice := o.GetPriceByConfig(defautPriceConfig)
-			if newPrice == nil {
-				return false
-			}
+		if !confirmed {
+			fmt.Println("> order cancelled")
+			return nil
+		}
+	}
 
-			currentPrice := current.GetPriceByConfig(defautPriceConfig)
-			if currentPrice == nil {
-				return false
-			}
+	if len(params.Datacenters) == 0 && len(candidates) == 0 {
+		return fmt.Errorf("--datacenters is required")
+	}
 
-			// Include option if its price is lower than the current one
-			return newPrice.PriceInUcents < currentPrice.PriceInUcents
+	// Read OVH API credentials from environment, the checkout preview needs an assigned cart
+	if !params.DryRun || params.Preview {
+		params.Credentials, err = ReadCredentials(params)
+		if err != nil {
+			return err
 		}
-
-		mandatoryOptions := ecoOptions.GetMandatoryOptions(optionFilter)
-		mergedOptions = userOptions.Merge(mandatoryOptions.ToOptions())
-		optionsCombinations = kimsufiorder.NewOptionsCombinationsFromSlice(mergedOptions)
 	}
 
-	fmt.Printf("> item options: %d %v\n", len(mergedOptions), mergedOptions.PlanCodes())
-	fmt.Printf("> datacenter(s): %d\n", len(datacenters))
-	fmt.Printf("> combinations: %d\n", len(optionsCombinations)*len(datacenters))
+	o := params.OrderOptions()
+	if params.Preview {
+		if len(candidates) > 0 || params.Parallel {
+			return fmt.Errorf("--preview can not be used with --candidate nor --parallel")
+		}
+
+		preview, err := orderflow.PreviewOrder(k, o)
+		if err != nil {
+			return fmt.Errorf("error: %w", err)
+		}
+		printPreview(preview)
 
-	// Stop on dry-run
-	if dryRun {
-		fmt.Println("> dry-run enabled, skipping order submission")
 		return nil
 	}
 
-	// Read OVH API credentials from environment
-	appKey := os.Getenv(cmd.Flag("ovh-app-key").Value.String())
-	if appKey == "" {
-		return fmt.Errorf("%s env var is required", cmd.Flag("ovh-app-key").Value.String())
-	}
-	appSecret := os.Getenv(cmd.Flag("ovh-app-secret").Value.String())
-	if appSecret == "" {
-		return fmt.Errorf("%s env var is required", cmd.Flag("ovh-app-secret").Value.String())
-	}
-	consumerKey := os.Getenv(cmd.Flag("ovh-consumer-key").Value.String())
-	if consumerKey == "" {
-		return fmt.Errorf("%s env var is required", cmd.Flag("ovh-consumer-key").Value.String())
+	var results []*orderflow.Result
+	switch {
+	case len(candidates) > 0:
+		results, err = orderflow.OrderCandidates(k, o, candidates, params.TotalQuantity)
+	case params.Parallel:
+		results, err = orderflow.OrderParallel(k, o, params.MaxOrders)
+	default:
+		var result *orderflow.Result
+		result, err = orderflow.Order(k, o)
+		results = append(results, result)
 	}
-
-	// Authenticate
-	k, err = k.WithAuth(appKey, appSecret, consumerKey)
 	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
 
-	// Assign cart to user account
-	err = k.AssignCart(cart.CartID)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
+	if followOrder && !params.DryRun {
+		return followResults(cmd.Context(), k, params.Credentials, results)
 	}
-	fmt.Println("> cart assigned")
 
-	// Try all options combinations
-	for _, options := range optionsCombinations {
-		// Configure item options
-		for _, option := range options {
-			err = k.ConfigureEcoItemOption(cart.CartID, item.ItemID, option, priceConfig)
-			if err != nil {
-				return fmt.Errorf("error: %w", err)
-			}
-			fmt.Printf("> cart option set: %s=%s\n", option.Family, option.PlanCode)
-		}
+	return nil
+}
 
-		// Try all datacenters
-		for _, datacenter := range datacenters {
-			datacenterConfiguration := kimsufiorder.ItemConfigurationRequest{
-				Label: kimsufiorder.ConfigurationLabelDatacenter,
-				Value: datacenter,
-			}
+// applySpec sets the spec values in params, except for the flags set on the command line, from the environment or from the profile.
+func applySpec(cmd *cobra.Command, spec *orderflow.Spec) {
+	flags := params.Options
+	spec.Apply(&params.Options)
 
-			resp, err := k.AddItemConfiguration(cart.CartID, item.ItemID, datacenterConfiguration)
-			if err != nil {
-				return fmt.Errorf("error: %w", err)
-			}
-			fmt.Printf("> datacenter %s configured\n", resp.Value)
+	changed := func(name string) bool {
+		return flag.IsSet(cmd, name)
+	}
 
-			// Checkout and complete the order
-			checkoutResp, err := k.CheckoutCart(cart.CartID, autoPay)
-			if err == nil {
-				fmt.Printf("> order completed: %s\n", checkoutResp.URL)
-				return nil
-			}
+	if changed(flag.PlanCodeFlagName) {
+		params.PlanCode = flags.PlanCode
+	}
+	if changed(flag.CountryFlagName) {
+		params.Subsidiary = flags.Subsidiary
+	}
+	if changed("datacenters") {
+		params.Datacenters = flags.Datacenters
+	}
+	if changed("quantity") {
+		params.Quantity = flags.Quantity
+	}
+	if changed("price-mode") {
+		params.PriceMode = flags.PriceMode
+	}
+	if changed("price-duration") {
+		params.PriceDuration = flags.PriceDuration
+	}
+	if changed("item-option") {
+		params.ItemOptions = flags.ItemOptions
+	}
+	if changed("item-configuration") {
+		params.ItemConfigurations = flags.ItemConfigurations
+	}
+	if changed("auto-pay") {
+		params.AutoPay = flags.AutoPay
+	}
+}
 
-			if kimsufi.IsNotAvailableError(err) {
-				fmt.Printf("> datacenter %s not available\n", datacenter)
-			} else {
-				fmt.Printf("> error: %v\n", err)
-			}
+// list prints the values requested by the --list-* flags.
+func list(k *kimsufi.Service, p Params) error {
+	inspection, err := orderflow.Inspect(k, p.Options)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
 
-			err = k.RemoveItemConfiguration(cart.CartID, item.ItemID, resp.ID)
-			if err != nil {
-				return fmt.Errorf("error: %w", err)
-			}
-		}
+	switch {
+	case p.ListOptions && inspection.IsVPSPlan:
+		printVPSItemOptions(inspection.VPSOptions, inspection.VPSPriceConfig)
+	case p.ListOptions:
+		printItemOptions(inspection.EcoOptions, inspection.EcoPriceConfig)
+	case p.ListPrices && inspection.IsVPSPlan:
+		return printVPSPrices(inspection.VPSInfos, p.PlanCode)
+	case p.ListPrices:
+		return printPrices(inspection.EcoInfos, p.PlanCode)
+	case p.ListConfigurations:
+		printConfigurations(inspection.RequiredConfigurations)
 	}
 
 	return nil
@@ -414,272 +514,6 @@ func printConfigurations(configurations []kimsufiorder.ItemConfiguration) {
 	}
 }
 
-// runnerVPS handles VPS plan ordering
-
-// runnerVPS handles VPS plan ordering
-func runnerVPS(cmd *cobra.Command, k *kimsufi.Service, ovhSubsidiary string) error {
-	// Create cart
-	expire := time.Now().AddDate(0, 0, 1)
-	cart, err := k.CreateCart(ovhSubsidiary, expire)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-	fmt.Printf("> cart created id=%s\n", cart.CartID)
-
-	// Retrieve VPS item options
-	vpsOptions, err := k.GetVPSOptions(cart.CartID, planCode)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-
-	priceConfig := kimsufiorder.VPSItemPriceConfig{
-		Duration:    priceDuration,
-		PricingMode: priceMode,
-	}
-
-	if listOptions {
-		printVPSItemOptions(vpsOptions, priceConfig)
-		return nil
-	}
-
-	// Retrieve VPS item information
-	vpsInfo, err := k.GetVPSInfo(cart.CartID, planCode)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-
-	if listPrices {
-		return printVPSPrices(vpsInfo, planCode)
-	}
-
-	// Ensure price config is valid, otherwise use default
-	priceConfig = vpsInfo.GetPriceConfigOrDefault(planCode, priceConfig)
-
-	// Add VPS plan to cart
-	item, err := k.AddVPSItem(cart.CartID, planCode, quantity, priceConfig)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-	fmt.Printf("> cart item added id=%d\n", item.ItemID)
-
-	requiredConfigurations, err := k.GetItemRequiredConfiguration(cart.CartID, int(item.ItemID))
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-
-	if listConfigurations {
-		printConfigurations(requiredConfigurations)
-		return nil
-	}
-
-	if len(datacenters) == 0 {
-		return fmt.Errorf("--datacenters is required")
-	}
-
-	// For VPS, validate datacenters against availability API
-	if slices.Contains(datacenters, anyOption) {
-		// Get available datacenters for this VPS plan
-		vpsAvailabilities, err := k.GetVPSAvailabilities(planCode, ovhSubsidiary, "")
-		if err != nil {
-			return fmt.Errorf("failed to get VPS availability: %w", err)
-		}
-		datacenters = vpsAvailabilities.GetAvailableDatacenterCodes()
-		if len(datacenters) == 0 {
-			return fmt.Errorf("no datacenters available for plan %s", planCode)
-		}
-		fmt.Printf("> using available datacenters: %s\n", strings.Join(datacenters, ", "))
-	}
-
-	// Prepare item configurations
-	itemConfigurations := kimsufiorder.NewItemConfigurationsFromMap(itemUserConfigurations)
-	r := kimsufiregion.GetRegionFromEndpoint(cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String())
-	if r != nil {
-		itemConfigurations.Add(kimsufiorder.ConfigurationLabelRegion, r.Region)
-	}
-
-	// Add datacenter configuration if specified
-	if len(datacenters) > 0 && !slices.Contains(datacenters, anyOption) {
-		// Use the first specified datacenter for VPS
-		itemConfigurations.Add(kimsufiorder.ConfigurationLabelDatacenter, datacenters[0])
-	}
-
-	autoConfigs := k.GenerateItemAutoConfigurations(requiredConfigurations)
-	userConfigs := autoConfigs.Merge(itemConfigurations)
-
-	// Handle VPS options - convert them to item configurations
-	var vpsOptionConfigs kimsufiorder.ItemConfigurationRequests
-	allOptions := slices.Contains(itemUserOptions, anyOption)
-	if allOptions {
-		// Get all mandatory options for VPS
-		mandatoryVPSOptions := vpsOptions.GetMandatoryOptions(nil)
-		for _, option := range mandatoryVPSOptions {
-			vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
-				Label: option.Family,
-				Value: option.PlanCode,
-			})
-		}
-	} else if len(itemUserOptions) > 0 {
-		userOptions, err := kimsufiorder.NewOptionsFromSlice(itemUserOptions)
-		if err != nil {
-			return fmt.Errorf("error: %w", err)
-		}
-		for _, option := range userOptions {
-			vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
-				Label: mapVPSOptionFamily(option.Family),
-				Value: option.PlanCode,
-			})
-		}
-	} else {
-		// If no options specified, try to get default mandatory options
-		mandatoryVPSOptions := vpsOptions.GetMandatoryOptions(nil)
-		if len(mandatoryVPSOptions) > 0 {
-			// Use the first available option for each mandatory family
-			for _, option := range mandatoryVPSOptions {
-				// For OS, prefer Linux options
-				if option.Family == "vps_os" {
-					// Look for Linux options first
-					for _, opt := range vpsOptions {
-						if opt.Family == "vps_os" && strings.Contains(strings.ToLower(opt.ProductName), "linux") {
-							vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
-								Label: opt.Family,
-								Value: opt.PlanCode,
-							})
-							break
-						}
-					}
-					if len(vpsOptionConfigs) == 0 || vpsOptionConfigs[len(vpsOptionConfigs)-1].Label != "vps_os" {
-						// Fallback to first OS option
-						vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
-							Label: option.Family,
-							Value: option.PlanCode,
-						})
-					}
-				} else {
-					// For other mandatory options, use the first available
-					vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
-						Label: option.Family,
-						Value: option.PlanCode,
-					})
-				}
-			}
-		}
-	}
-
-	// Merge VPS option configurations with user configurations
-	userConfigs = userConfigs.Merge(vpsOptionConfigs)
-
-	// For VPS, skip manual configuration if datacenter is already specified
-	var configurations kimsufiorder.ItemConfigurationRequests
-	if len(datacenters) > 0 && !slices.Contains(datacenters, anyOption) {
-		// All required configurations should be available, skip manual input
-		configurations = userConfigs
-	} else {
-		manualConfigs, err := generateItemManualConfiguration(userConfigs, requiredConfigurations)
-		if err != nil {
-			return fmt.Errorf("error: %w", err)
-		}
-		configurations = userConfigs.Merge(manualConfigs)
-	}
-
-	// Configure item
-	for _, configuration := range configurations {
-		resp, err := k.AddItemConfiguration(cart.CartID, int(item.ItemID), configuration)
-		if err != nil {
-			return fmt.Errorf("error: %w", err)
-		}
-		fmt.Printf("> cart item configured: %s=%s\n", resp.Label, resp.Value)
-	}
-
-	fmt.Printf("> vps options configured: %d\n", len(vpsOptionConfigs))
-	fmt.Printf("> datacenter(s): %d\n", len(datacenters))
-
-	// Stop on dry-run
-	if dryRun {
-		fmt.Println("> dry-run enabled, skipping order submission")
-		return nil
-	}
-
-	// Read OVH API credentials from environment
-	appKey := os.Getenv(ovhAppKeyEnvVarName)
-	if appKey == "" {
-		return fmt.Errorf("%s env var is required", ovhAppKeyEnvVarName)
-	}
-	appSecret := os.Getenv(ovhAppSecretEnvVarName)
-	if appSecret == "" {
-		return fmt.Errorf("%s env var is required", ovhAppSecretEnvVarName)
-	}
-	consumerKey := os.Getenv(ovhConsumerKeyEnvVarName)
-	if consumerKey == "" {
-		return fmt.Errorf("%s env var is required", ovhConsumerKeyEnvVarName)
-	}
-
-	// Authenticate
-	k, err = k.WithAuth(appKey, appSecret, consumerKey)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-
-	// Assign cart to user account
-	err = k.AssignCart(cart.CartID)
-	if err != nil {
-		return fmt.Errorf("error: %w", err)
-	}
-	fmt.Println("> cart assigned")
-
-	// For VPS, use the configured datacenter or try all available ones
-	var datacentersToTry []string
-	if len(datacenters) > 0 && !slices.Contains(datacenters, anyOption) {
-		// Use the specific datacenter that was configured
-		datacentersToTry = []string{datacenters[0]}
-	} else {
-		// Try all available datacenters
-		datacentersToTry = datacenters
-	}
-
-	for _, datacenter := range datacentersToTry {
-		// Only configure datacenter if it's not already configured
-		var resp *kimsufiorder.ItemConfigurationResponse
-		if userConfigs.GetByLabel(kimsufiorder.ConfigurationLabelDatacenter) == nil {
-			datacenterConfiguration := kimsufiorder.ItemConfigurationRequest{
-				Label: kimsufiorder.ConfigurationLabelDatacenter,
-				Value: datacenter,
-			}
-
-			var err error
-			resp, err = k.AddItemConfiguration(cart.CartID, int(item.ItemID), datacenterConfiguration)
-			if err != nil {
-				return fmt.Errorf("error: %w", err)
-			}
-			fmt.Printf("> datacenter %s configured\n", resp.Value)
-		} else {
-			fmt.Printf("> using pre-configured datacenter: %s\n", datacenter)
-		}
-
-		// Checkout and complete the order
-		checkoutResp, err := k.CheckoutCart(cart.CartID, autoPay)
-		if err == nil {
-			fmt.Printf("> order completed: %s\n", checkoutResp.URL)
-			return nil
-		}
-
-		if kimsufi.IsNotAvailableError(err) {
-			fmt.Printf("> datacenter %s not available\n", datacenter)
-		} else {
-			fmt.Printf("> error: %v\n", err)
-		}
-
-		// Remove datacenter configuration if we added it
-		if resp != nil {
-			err = k.RemoveItemConfiguration(cart.CartID, int(item.ItemID), resp.ID)
-			if err != nil {
-				return fmt.Errorf("error: %w", err)
-			}
-		}
-	}
-
-	return fmt.Errorf("no available datacenters for VPS plan %s", planCode)
-}
-
 func printVPSItemOptions(options kimsufiorder.VPSItemOptions, priceConfig kimsufiorder.VPSItemPriceConfig) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
 	fmt.Fprintln(w, "item-option\tname\tprice")
@@ -0,0 +1,48 @@
+package order
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderflow"
+)
+
+// printPreview prints the itemised checkout preview.
+func printPreview(p *orderflow.Preview) {
+	fmt.Printf("> checkout preview of %s in %s, cart %s\n", p.PlanCode, p.Datacenter, p.CartID)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "description\ttype\tquantity\tunit-price\ttotal-price")
+	fmt.Fprintln(w, "-----------\t----\t--------\t----------\t-----------")
+	for _, d := range p.Checkout.Details {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Description, d.DetailType, d.Quantity, d.UnitPrice.Text, d.TotalPrice.Text)
+	}
+	w.Flush()
+	fmt.Println()
+
+	prices := p.Checkout.Prices
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "original price without tax\t%s\n", prices.OriginalWithoutTax.Text)
+	fmt.Fprintf(w, "reduction\t%s\n", prices.Reduction.Text)
+	fmt.Fprintf(w, "price without tax\t%s\n", prices.WithoutTax.Text)
+	fmt.Fprintf(w, "tax\t%s\n", prices.Tax.Text)
+	fmt.Fprintf(w, "price with tax\t%s\n", prices.WithTax.Text)
+	fmt.Fprintf(w, "installation fees without tax\t%.2f %s\n", p.Prices.Setup, p.Prices.CurrencyCode)
+	fmt.Fprintf(w, "renewal price without tax\t%.2f %s every %s\n", p.Renewal, p.Prices.CurrencyCode, p.PriceDuration)
+	w.Flush()
+
+	if len(p.Checkout.Contracts) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "contract\turl")
+	fmt.Fprintln(w, "--------\t---")
+	for _, c := range p.Checkout.Contracts {
+		fmt.Fprintf(w, "%s\t%s\n", c.Name, c.URL)
+	}
+	w.Flush()
+}
@@ -0,0 +1,152 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderflow"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/ordertrack"
+)
+
+var (
+	statusCmd = &cobra.Command{
+		Use:   "status <orderId>",
+		Short: "Show the status of an order",
+		Long:  "Show the payment and delivery status of an order placed with the account, with --follow it is polled until the order is delivered or cancelled",
+		Example: `  kimsufi-notifier order status 123456789
+  kimsufi-notifier order status 123456789 --follow --notify-webhook https://example.com/hook`,
+		Args: cobra.ExactArgs(1),
+		RunE: statusRunner,
+	}
+)
+
+func init() {
+	Cmd.AddCommand(statusCmd)
+}
+
+func statusRunner(cmd *cobra.Command, args []string) error {
+	orderID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", args[0], err)
+	}
+
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+
+	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	params.Endpoint = endpoint
+	k, err = Authenticate(k, params)
+	if err != nil {
+		return err
+	}
+
+	return follow(cmd.Context(), k, []int{orderID}, true, false)
+}
+
+// followResults follows the completed orders together, see follow.
+// An order may not be visible right after its checkout, so failing to check it is not an error.
+func followResults(ctx context.Context, k *kimsufi.Service, credentials *orderflow.Credentials, results []*orderflow.Result) error {
+	k, err := k.WithAuth(credentials.AppKey, credentials.AppSecret, credentials.ConsumerKey)
+	if err != nil {
+		return err
+	}
+
+	var orderIDs []int
+	for _, result := range results {
+		if result == nil || !result.Completed() || result.Checkout.OrderID == 0 {
+			continue
+		}
+		orderIDs = append(orderIDs, result.Checkout.OrderID)
+	}
+
+	return follow(ctx, k, orderIDs, false, true)
+}
+
+// follow prints the status of the orders and notifies each of their steps, polling them until they are all delivered or cancelled when --follow is set.
+// It stops when ctx is done, k must be authenticated. When printDetails is true, the details of each order are printed once.
+// Failing to check an order for the first time is an error, unless retry is true in which case it is checked again on the next poll.
+func follow(ctx context.Context, k *kimsufi.Service, orderIDs []int, printDetails, retry bool) error {
+	n := notifier.New(os.Stdout, webhooks)
+
+	previous := make(map[int]*ordertrack.Snapshot)
+	pending := orderIDs
+	for len(pending) > 0 {
+		var next []int
+		for _, orderID := range pending {
+			s, err := ordertrack.Fetch(k, orderID, time.Now())
+			if err != nil {
+				if previous[orderID] == nil && !retry {
+					return fmt.Errorf("error: %w", err)
+				}
+				log.Errorf("failed to check order %d: %v", orderID, err)
+				next = append(next, orderID)
+				continue
+			}
+
+			if previous[orderID] == nil && printDetails {
+				printSnapshot(s)
+			}
+
+			events := s.Events(previous[orderID])
+			err = notifier.NotifyAll(n, events)
+			if err != nil {
+				log.Warnf("failed to notify order status: %v", err)
+			}
+			previous[orderID] = s
+
+			if !s.IsFinal() {
+				next = append(next, orderID)
+			}
+		}
+
+		pending = next
+		if !followOrder || len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("> stop following")
+			return nil
+		case <-time.After(followInterval):
+		}
+	}
+
+	return nil
+}
+
+// printSnapshot prints the order and its details.
+func printSnapshot(s *ordertrack.Snapshot) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "order\t%d\n", s.Order.OrderID)
+	fmt.Fprintf(w, "date\t%s\n", s.Order.Date.Local().Format(time.DateTime))
+	fmt.Fprintf(w, "status\t%s\n", s.Status)
+	fmt.Fprintf(w, "payment\t%s\n", s.Payment())
+	fmt.Fprintf(w, "price with tax\t%s\n", s.Order.PriceWithTax.Text)
+	fmt.Fprintf(w, "url\t%s\n", s.Order.URL)
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "description\ttype\tquantity\ttotal-price")
+	fmt.Fprintln(w, "-----------\t----\t--------\t-----------")
+	for _, d := range s.Details {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Description, d.DetailType, d.Quantity, d.TotalPrice.Text)
+	}
+	w.Flush()
+	fmt.Println()
+}
@@ -0,0 +1,406 @@
+package order
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+	kimsufiregion "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/region"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderflow"
+)
+
+// wizard walks the user through the order: subsidiary, plan, price, options, configurations and datacenter.
+// Values already set in p are kept, except the subsidiary which is proposed among the endpoint countries.
+// It ends on a summary of the order and returns true when the user confirms it.
+func wizard(k *kimsufi.Service, p *Params) (bool, error) {
+	err := chooseSubsidiary(p)
+	if err != nil {
+		return false, err
+	}
+
+	if p.PlanCode == "" {
+		err = choosePlan(k, p)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	inspection, err := orderflow.Inspect(k, p.Options)
+	if err != nil {
+		return false, err
+	}
+
+	err = choosePrice(inspection, p)
+	if err != nil {
+		return false, err
+	}
+
+	if len(p.ItemOptions) == 0 {
+		err = chooseOptions(inspection, p)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = chooseConfigurations(inspection, p)
+	if err != nil {
+		return false, err
+	}
+
+	if len(p.Datacenters) == 0 {
+		err = chooseDatacenter(k, inspection, p)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	printSummary(*p)
+
+	return confirm("Place the order?")
+}
+
+// chooseSubsidiary asks for the subsidiary among the countries of the endpoint.
+func chooseSubsidiary(p *Params) error {
+	region := kimsufiregion.GetRegionFromEndpoint(p.Endpoint)
+	if region == nil {
+		return nil
+	}
+
+	var countries []string
+	for _, c := range region.Countries {
+		countries = append(countries, c.Code)
+	}
+	if len(countries) <= 1 {
+		return nil
+	}
+
+	choice, err := choose(fmt.Sprintf("select a country (current: %s)", p.Subsidiary), countries)
+	if err != nil {
+		return err
+	}
+	p.Subsidiary = countries[choice]
+
+	return nil
+}
+
+// choosePlan asks for an Eco or VPS plan, showing its price and live availability.
+func choosePlan(k *kimsufi.Service, p *Params) error {
+	catalog, err := k.ListServers(p.Subsidiary)
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	vpsCatalog, err := k.ListVPSServers(p.Subsidiary)
+	if err != nil {
+		return fmt.Errorf("failed to list VPS servers: %w", err)
+	}
+
+	availabilities, err := k.GetAvailabilities(nil, "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to list availabilities: %w", err)
+	}
+
+	plans := catalog.Plans
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].GetFirstPrice().Price < plans[j].GetFirstPrice().Price
+	})
+
+	var planCodes, values []string
+	for _, plan := range plans {
+		status := "unavailable"
+		datacenters := availabilities.GetByPlanCode(plan.PlanCode).GetAvailableDatacenters().Codes()
+		if len(datacenters) > 0 {
+			status = "available in " + strings.Join(datacenters, ", ")
+		}
+
+		planCodes = append(planCodes, plan.PlanCode)
+		values = append(values, fmt.Sprintf("%s\t%s\t%.2f %s\t%s", plan.PlanCode, plan.InvoiceName, plan.GetFirstPrice().GetPrice(), catalog.Locale.CurrencyCode, status))
+	}
+
+	// VPS availabilities are only listed per plan.
+	vpsPlans := vpsCatalog.Plans
+	sort.SliceStable(vpsPlans, func(i, j int) bool {
+		return vpsPlans[i].GetFirstPrice().Price < vpsPlans[j].GetFirstPrice().Price
+	})
+
+	for _, plan := range vpsPlans {
+		status := "unknown"
+		vpsAvailabilities, err := k.GetVPSAvailabilities(plan.PlanCode, p.Subsidiary, "")
+		if err == nil {
+			status = "unavailable"
+			datacenters := vpsAvailabilities.GetAvailableDatacenterCodes()
+			if len(datacenters) > 0 {
+				status = "available in " + strings.Join(datacenters, ", ")
+			}
+		}
+
+		planCodes = append(planCodes, plan.PlanCode)
+		values = append(values, fmt.Sprintf("%s\t%s\t%.2f %s\t%s", plan.PlanCode, plan.InvoiceName, plan.GetFirstPrice().GetPrice(), vpsCatalog.Locale.CurrencyCode, status))
+	}
+
+	choice, err := choose("select a plan", alignValues(values))
+	if err != nil {
+		return err
+	}
+	p.PlanCode = planCodes[choice]
+
+	return nil
+}
+
+// wizardPrice is a price of the plan proposed by the wizard.
+type wizardPrice struct {
+	Duration    string
+	PricingMode string
+	Text        string
+	Description string
+}
+
+// wizardOption is an option of the plan proposed by the wizard, with its price for the chosen duration and mode.
+type wizardOption struct {
+	Family      string
+	PlanCode    string
+	ProductName string
+	Price       string
+	Mandatory   bool
+}
+
+// planPrices returns the prices of the inspected Eco or VPS plan.
+func planPrices(inspection *orderflow.Inspection) ([]wizardPrice, error) {
+	var prices []wizardPrice
+
+	if inspection.IsVPSPlan {
+		info := inspection.VPSInfos.GetByPlanCode(inspection.PlanCode)
+		if info == nil {
+			return nil, fmt.Errorf("VPS plan %s not found", inspection.PlanCode)
+		}
+		for _, price := range info.Prices {
+			prices = append(prices, wizardPrice{Duration: price.Duration, PricingMode: price.PricingMode, Text: price.Price.Text, Description: price.Description})
+		}
+
+		return prices, nil
+	}
+
+	info := inspection.EcoInfos.GetByPlanCode(inspection.PlanCode)
+	if info == nil {
+		return nil, fmt.Errorf("plan %s not found", inspection.PlanCode)
+	}
+	for _, price := range info.Prices {
+		prices = append(prices, wizardPrice{Duration: price.Duration, PricingMode: price.PricingMode, Text: price.Price.Text, Description: price.Description})
+	}
+
+	return prices, nil
+}
+
+// planOptions returns the options of the inspected Eco or VPS plan, priced for the duration and mode of p.
+func planOptions(inspection *orderflow.Inspection, p Params) []wizardOption {
+	var options []wizardOption
+
+	if inspection.IsVPSPlan {
+		priceConfig := kimsufiorder.VPSItemPriceConfig{Duration: p.PriceDuration, PricingMode: p.PriceMode}
+		for _, o := range inspection.VPSOptions {
+			option := wizardOption{Family: o.Family, PlanCode: o.PlanCode, ProductName: o.ProductName, Mandatory: o.Mandatory}
+			if op := o.GetPriceByConfig(priceConfig); op != nil {
+				option.Price = op.Price.Text
+			}
+			options = append(options, option)
+		}
+
+		return options
+	}
+
+	priceConfig := kimsufiorder.EcoItemPriceConfig{Duration: p.PriceDuration, PricingMode: p.PriceMode}
+	for _, o := range inspection.EcoOptions {
+		option := wizardOption{Family: o.Family, PlanCode: o.PlanCode, ProductName: o.ProductName, Mandatory: o.Mandatory}
+		if op := o.GetPriceByConfig(priceConfig); op != nil {
+			option.Price = op.Price.Text
+		}
+		options = append(options, option)
+	}
+
+	return options
+}
+
+// choosePrice asks for the price mode and duration of the plan.
+func choosePrice(inspection *orderflow.Inspection, p *Params) error {
+	prices, err := planPrices(inspection)
+	if err != nil {
+		return err
+	}
+
+	var values []string
+	for _, price := range prices {
+		values = append(values, fmt.Sprintf("%s\t%s\t%s\t%s", price.Duration, price.PricingMode, price.Text, price.Description))
+	}
+	if len(values) <= 1 {
+		return nil
+	}
+
+	choice, err := choose("select a price duration and mode", alignValues(values))
+	if err != nil {
+		return err
+	}
+	p.PriceDuration = prices[choice].Duration
+	p.PriceMode = prices[choice].PricingMode
+
+	return nil
+}
+
+// chooseOptions asks for an option in each mandatory family offering more than one,
+// and whether to add an option of each optional family.
+func chooseOptions(inspection *orderflow.Inspection, p *Params) error {
+	families := make(map[string][]wizardOption)
+	var order []string
+	for _, o := range planOptions(inspection, *p) {
+		if _, ok := families[o.Family]; !ok {
+			order = append(order, o.Family)
+		}
+		families[o.Family] = append(families[o.Family], o)
+	}
+
+	for _, family := range order {
+		options := families[family]
+
+		var values []string
+		for _, o := range options {
+			values = append(values, fmt.Sprintf("%s\t%s\t%s", o.PlanCode, o.ProductName, o.Price))
+		}
+
+		choice, err := chooseOption(family, options[0].Mandatory, values)
+		if err != nil {
+			return err
+		}
+		if choice < 0 {
+			continue
+		}
+		p.ItemOptions = append(p.ItemOptions, fmt.Sprintf("%s=%s", family, options[choice].PlanCode))
+	}
+
+	return nil
+}
+
+// chooseOption asks for an option of family among values and returns its index.
+// A mandatory family with a single option is selected without asking,
+// an optional family can be left out, in which case -1 is returned.
+func chooseOption(family string, mandatory bool, values []string) (int, error) {
+	if mandatory {
+		if len(values) == 1 {
+			return 0, nil
+		}
+
+		return choose(fmt.Sprintf("select a %s option", family), alignValues(values))
+	}
+
+	choice, err := choose(fmt.Sprintf("select a %s option (optional)", family), append([]string{"none"}, alignValues(values)...))
+	if err != nil {
+		return 0, err
+	}
+
+	return choice - 1, nil
+}
+
+// chooseConfigurations asks for the required configurations which are not set and offer more than one value,
+// the datacenter is asked separately.
+func chooseConfigurations(inspection *orderflow.Inspection, p *Params) error {
+	var missing []kimsufiorder.ItemConfiguration
+	for _, c := range inspection.RequiredConfigurations {
+		if !c.Required || len(c.AllowedValues) <= 1 {
+			continue
+		}
+		if c.Label == kimsufiorder.ConfigurationLabelDatacenter || c.Label == kimsufiorder.ConfigurationLabelVPSDatacenter {
+			continue
+		}
+		if _, ok := p.ItemConfigurations[c.Label]; ok {
+			continue
+		}
+		missing = append(missing, c)
+	}
+
+	configurations, err := generateItemManualConfiguration(missing)
+	if err != nil {
+		return err
+	}
+
+	if len(configurations) > 0 && p.ItemConfigurations == nil {
+		p.ItemConfigurations = make(map[string]string)
+	}
+	for _, c := range configurations {
+		p.ItemConfigurations[c.Label] = c.Value
+	}
+
+	return nil
+}
+
+// chooseDatacenter asks for a datacenter among the ones where the plan is available.
+func chooseDatacenter(k *kimsufi.Service, inspection *orderflow.Inspection, p *Params) error {
+	var available []string
+	if inspection.IsVPSPlan {
+		availabilities, err := k.GetVPSAvailabilities(inspection.PlanCode, p.Subsidiary, "")
+		if err != nil {
+			return fmt.Errorf("failed to list availabilities: %w", err)
+		}
+		available = availabilities.GetAvailableDatacenterCodes()
+	} else {
+		availabilities, err := k.GetAvailabilities(nil, inspection.PlanCode, nil)
+		if err != nil && !kimsufi.IsAvailabilityNotFoundError(err) {
+			return fmt.Errorf("failed to list availabilities: %w", err)
+		}
+		if err == nil {
+			available = availabilities.GetAvailableDatacenters().Codes()
+		}
+	}
+
+	if len(available) == 0 {
+		fmt.Printf("> plan %s is not available in any datacenter right now\n", inspection.PlanCode)
+	}
+
+	values := append(slices.Clone(available), orderflow.AnyDatacenter)
+	choice, err := choose("select a datacenter", values)
+	if err != nil {
+		return err
+	}
+	p.Datacenters = []string{values[choice]}
+
+	return nil
+}
+
+// printSummary prints the order before checkout.
+func printSummary(p Params) {
+	fmt.Println()
+	fmt.Println("> order summary")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "country\t%s\n", p.Subsidiary)
+	fmt.Fprintf(w, "plan\t%s\n", p.PlanCode)
+	fmt.Fprintf(w, "price\t%s %s\n", p.PriceDuration, p.PriceMode)
+	fmt.Fprintf(w, "options\t%s\n", strings.Join(p.ItemOptions, ", "))
+
+	var configurations []string
+	for _, label := range slices.Sorted(maps.Keys(p.ItemConfigurations)) {
+		configurations = append(configurations, fmt.Sprintf("%s=%s", label, p.ItemConfigurations[label]))
+	}
+	fmt.Fprintf(w, "configurations\t%s\n", strings.Join(configurations, ", "))
+	fmt.Fprintf(w, "datacenters\t%s\n", strings.Join(p.Datacenters, ", "))
+	fmt.Fprintf(w, "quantity\t%d\n", p.Quantity)
+	fmt.Fprintf(w, "auto-pay\t%t\n", p.AutoPay)
+	fmt.Fprintf(w, "dry-run\t%t\n", p.DryRun)
+	w.Flush()
+	fmt.Println()
+}
+
+// alignValues aligns the tab separated columns of the values.
+func alignValues(values []string) []string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 4, ' ', 0)
+	for _, v := range values {
+		fmt.Fprintln(w, v)
+	}
+	w.Flush()
+
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/ordertrack"
+)
+
+var (
+	cancelUnpaidCmd = &cobra.Command{
+		Use:   "cancel-unpaid",
+		Short: "Request the retraction of old unpaid orders",
+		Long:  "Request the retraction of the orders which are still not paid after --older-than\n\nthe OVH API has no call to cancel an order, the retraction is the withdrawal request and it is not confirmed that it cancels an unpaid order: check the orders status afterwards with orders list, unpaid orders otherwise expire on their own at their expiration date",
+		Example: `  kimsufi-notifier orders cancel-unpaid --older-than 24h
+  kimsufi-notifier orders cancel-unpaid --older-than 7d --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: cancelUnpaidRunner,
+	}
+
+	// Flags variables
+	cancelDryRun     bool
+	olderThan        string
+	retractionReason string
+	cancelSince      string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	cancelUnpaidCmd.Flags().StringVar(&olderThan, "older-than", "24h", "only retract orders placed at least this long ago (e.g. 36h, 7d)")
+	cancelUnpaidCmd.Flags().StringVar(&cancelSince, flag.SinceFlagName, "30d", "only retract orders placed after this time, as a date, a RFC 3339 timestamp or a duration ago, empty for all orders")
+	cancelUnpaidCmd.Flags().StringVar(&retractionReason, "reason", kimsufiorder.RetractionReasonUnused, fmt.Sprintf("retraction reason (allowed values: %s)", strings.Join(kimsufiorder.RetractionReasons, ", ")))
+	cancelUnpaidCmd.Flags().BoolVarP(&cancelDryRun, "dry-run", "n", false, "only list the orders whose retraction would be requested")
+
+	Cmd.AddCommand(cancelUnpaidCmd)
+}
+
+// cancelUnpaidRunner is the main function for the orders cancel-unpaid command
+func cancelUnpaidRunner(cmd *cobra.Command, args []string) error {
+	age, err := history.ParseDuration(olderThan)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+
+	k, err := newService(cmd)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := history.ParseTime(cancelSince, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.SinceFlagName, err)
+	}
+
+	until := now.Add(-age)
+	if !since.IsZero() && !since.Before(until) {
+		return fmt.Errorf("--older-than %s is beyond --%s %s, no order can match", olderThan, flag.SinceFlagName, cancelSince)
+	}
+
+	// Each order is fetched, only the ones placed within --since are listed.
+	f := ordertrack.Filter{
+		Since:    since,
+		Until:    until,
+		Statuses: []string{kimsufiorder.OrderStatusNotPaid},
+	}
+
+	snapshots, err := ordertrack.List(k, f, now)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	var failed int
+	for _, s := range snapshots {
+		if !s.IsUnpaidSince(age) {
+			continue
+		}
+
+		if cancelDryRun {
+			fmt.Printf("> order %d placed %s would be retracted: %s\n", s.Order.OrderID, s.Order.Date.Local().Format(time.DateTime), s.Order.PriceWithTax.Text)
+			continue
+		}
+
+		err := k.RetractOrder(s.Order.OrderID, retractionReason, "unpaid order")
+		if err != nil {
+			log.Errorf("failed to request the retraction of order %d: %v", s.Order.OrderID, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("> order %d placed %s: retraction requested\n", s.Order.OrderID, s.Order.Date.Local().Format(time.DateTime))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("error: failed to request the retraction of %d order(s)", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package orders
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/ordertrack"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+)
+
+var (
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List past orders",
+		Long:  "List the orders placed with the account with their status, amount and URL, newest first",
+		Example: `  kimsufi-notifier orders list
+  kimsufi-notifier orders list --since 30d --status notPaid
+  kimsufi-notifier orders list --since 2025-01-01 --until 2025-02-01 --output json`,
+		Args: cobra.NoArgs,
+		RunE: listRunner,
+	}
+
+	// Flags variables
+	outputFormat string
+	since        string
+	statuses     []string
+	timezone     string
+	until        string
+)
+
+// init registers the subcommand and its flags
+func init() {
+	flag.BindOutputFlag(listCmd, &outputFormat)
+	flag.BindTimeRangeFlags(listCmd, &since, &until)
+	flag.BindTimezoneFlag(listCmd, &timezone)
+	listCmd.Flags().StringSliceVar(&statuses, "status", nil, fmt.Sprintf("only include orders with these statuses, comma separated list (known values: %s)", strings.Join(kimsufiorder.OrderStatuses, ", ")))
+
+	Cmd.AddCommand(listCmd)
+}
+
+// listRunner is the main function for the orders list command
+func listRunner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.TimezoneFlagName, err)
+	}
+
+	now := time.Now()
+	f := ordertrack.Filter{Statuses: statuses}
+
+	f.Since, err = history.ParseTime(since, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.SinceFlagName, err)
+	}
+
+	f.Until, err = history.ParseTime(until, now)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.UntilFlagName, err)
+	}
+
+	k, err := newService(cmd)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := ordertrack.List(k, f, now)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	headers := []string{"orderId", "date", "status", "amount", "url"}
+	var rows [][]string
+	for _, s := range snapshots {
+		rows = append(rows, []string{
+			strconv.Itoa(s.Order.OrderID),
+			s.Order.Date.In(loc).Format(time.DateTime),
+			s.Status,
+			s.Order.PriceWithTax.Text,
+			s.Order.URL,
+		})
+	}
+
+	return output.Write(os.Stdout, outputFormat, headers, rows, snapshots)
+}
@@ -0,0 +1,56 @@
+package orders
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "orders",
+		Short: "Manage past orders",
+		Long:  "List the orders placed with the account, pay them and request the retraction of the unpaid ones",
+		Example: `  kimsufi-notifier orders list --since 30d
+  kimsufi-notifier orders pay 123456789
+  kimsufi-notifier orders cancel-unpaid --older-than 24h`,
+	}
+
+	// Flags variables
+	credentials order.Params
+)
+
+// init registers all flags
+func init() {
+	order.BindCredentialsFlags(Cmd, &credentials)
+}
+
+// newService returns a Service authenticated with the OVH API credentials.
+func newService(cmd *cobra.Command) (*kimsufi.Service, error) {
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+
+	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	credentials.Endpoint = endpoint
+	return order.Authenticate(k, credentials)
+}
+
+// parseOrderID parses an order ID argument.
+func parseOrderID(value string) (int, error) {
+	orderID, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid order ID %q: %w", value, err)
+	}
+
+	return orderID, nil
+}
@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+var (
+	payCmd = &cobra.Command{
+		Use:   "pay <orderId>",
+		Short: "Pay an order",
+		Long:  "Pay an unpaid order with a payment method registered on the account, the default one unless --payment-method is set",
+		Example: `  kimsufi-notifier orders pay 123456789
+  kimsufi-notifier orders pay 123456789 --payment-method 42`,
+		Args: cobra.ExactArgs(1),
+		RunE: payRunner,
+	}
+
+	// Flags variables
+	paymentMethodID int
+)
+
+// init registers the subcommand and its flags
+func init() {
+	payCmd.Flags().IntVar(&paymentMethodID, "payment-method", 0, "ID of the registered payment method to pay with, the default one when unset")
+
+	Cmd.AddCommand(payCmd)
+}
+
+// payRunner is the main function for the orders pay command
+func payRunner(cmd *cobra.Command, args []string) error {
+	orderID, err := parseOrderID(args[0])
+	if err != nil {
+		return err
+	}
+
+	k, err := newService(cmd)
+	if err != nil {
+		return err
+	}
+
+	paymentMethods, err := k.ListPaymentMethods()
+	if err != nil {
+		return fmt.Errorf("error: failed to list payment methods: %w", err)
+	}
+
+	var paymentMethod *kimsufiorder.PaymentMethod
+	if paymentMethodID != 0 {
+		paymentMethod = paymentMethods.GetByID(paymentMethodID)
+		if paymentMethod == nil {
+			return fmt.Errorf("payment method %d not found (available: %s)", paymentMethodID, paymentMethodList(paymentMethods))
+		}
+	} else {
+		paymentMethod = paymentMethods.GetDefault()
+		if paymentMethod == nil {
+			return fmt.Errorf("no default payment method, --payment-method is required (available: %s)", paymentMethodList(paymentMethods))
+		}
+	}
+
+	err = k.PayOrder(orderID, paymentMethod.PaymentMethodID)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	fmt.Printf("> order %d paid with %s\n", orderID, paymentMethodString(*paymentMethod))
+
+	return nil
+}
+
+// paymentMethodString returns the payment method ID, type and label.
+func paymentMethodString(m kimsufiorder.PaymentMethod) string {
+	return fmt.Sprintf("%d %s %s", m.PaymentMethodID, m.PaymentType, m.Label)
+}
+
+// paymentMethodList returns a human readable list of payment methods.
+func paymentMethodList(paymentMethods kimsufiorder.PaymentMethods) string {
+	if len(paymentMethods) == 0 {
+		return "none"
+	}
+
+	var values []string
+	for _, m := range paymentMethods {
+		values = append(values, paymentMethodString(m))
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -7,6 +7,26 @@ import (
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
 )
 
+// preRun applies the configuration profile, the OVH API retry policy, rate limit and disk cache, then sets the log level.
+func preRun(cmd *cobra.Command, args []string) error {
+	err := flag.ApplyConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = flag.ApplyAPIPolicy(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = flag.ApplyCache(cmd)
+	if err != nil {
+		return err
+	}
+
+	return logLevel(cmd, args)
+}
+
 // logLevel set the logger log level using the value of the flag
 func logLevel(cmd *cobra.Command, args []string) error {
 	level, err := log.ParseLevel(cmd.Flag(flag.LogLevelFlagName).Value.String())
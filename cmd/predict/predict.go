@@ -0,0 +1,109 @@
+package predict
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	cmdhistory "github.com/TheoBrigitte/kimsufi-notifier/cmd/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "predict",
+		Short: "Predict restocks",
+		Long:  "Estimate the probability of a restock in the next hours per datacenter from the availability history\n\nthe probability combines restock rates by hour of the day and day of the week\nwith the distribution of times between restocks",
+		Example: `  kimsufi-notifier predict --plan-code 24ska01
+  kimsufi-notifier predict --plan-code 24ska01 --hours 6 --datacenters gra,rbx
+  kimsufi-notifier predict --plan-code 24ska01 --output json`,
+		RunE: runner,
+	}
+
+	// Flags variables
+	datacenters  []string
+	historyFile  string
+	hours        int
+	outputFormat string
+	planCode     string
+	since        string
+	timezone     string
+)
+
+// init registers all flags
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &planCode)
+	flag.BindDatacentersFlag(Cmd, &datacenters)
+	flag.BindHistoryFileFlag(Cmd, &historyFile)
+	flag.BindOutputFlag(Cmd, &outputFormat)
+	flag.BindTimezoneFlag(Cmd, &timezone)
+
+	Cmd.PersistentFlags().IntVar(&hours, "hours", 24, "prediction horizon in hours")
+	Cmd.PersistentFlags().StringVar(&since, flag.SinceFlagName, "", "only learn from history after this time, as a date, a RFC 3339 timestamp or a duration ago (e.g. 2024-01-31, 30d)")
+}
+
+// runner is the main function for the predict command
+func runner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	// Flag validation
+	if planCode == "" {
+		return fmt.Errorf("--%s is required", flag.PlanCodeFlagName)
+	}
+	if hours <= 0 {
+		return fmt.Errorf("--hours must be positive")
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.TimezoneFlagName, err)
+	}
+
+	transitions, err := cmdhistory.LoadTransitions(cmd, historyFile, planCode, datacenters, since, "")
+	if err != nil {
+		return err
+	}
+	if len(transitions) == 0 {
+		return fmt.Errorf("no history found for %s, record some with: kimsufi-notifier check --plan-code %s --record", planCode, planCode)
+	}
+
+	predictions := history.Predict(transitions, time.Now(), time.Duration(hours)*time.Hour, loc)
+
+	headers := []string{"datacenter", "probability", "seasonal", "interArrival", "windows", "lastRestock", "explanation"}
+	var rows [][]string
+	for _, p := range predictions {
+		interArrival := "-"
+		if p.InterArrival != nil {
+			interArrival = formatPercent(*p.InterArrival)
+		}
+
+		lastRestock := "-"
+		if !p.LastRestock.IsZero() {
+			lastRestock = p.LastRestock.In(loc).Format(time.DateTime)
+		}
+
+		rows = append(rows, []string{
+			p.Datacenter,
+			formatPercent(p.Probability),
+			formatPercent(p.Seasonal),
+			interArrival,
+			strconv.Itoa(p.Windows),
+			lastRestock,
+			p.Explanation,
+		})
+	}
+
+	return output.Write(os.Stdout, outputFormat, headers, rows, predictions)
+}
+
+func formatPercent(f float64) string {
+	return strconv.FormatFloat(f*100, 'f', 1, 64) + "%"
+}
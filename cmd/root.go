@@ -1,15 +1,27 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/auth"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/cache"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/catalog"
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/check"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/compareprices"
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/history"
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/list"
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/orders"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/predict"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/stats"
 	"github.com/TheoBrigitte/kimsufi-notifier/cmd/version"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/watch"
 )
 
 // rootCmd represents the base command when called without any arguments
@@ -17,7 +29,7 @@ var rootCmd = &cobra.Command{
 	Use:               "kimsufi-notifier",
 	Short:             "kimsufi availability notifier",
 	Long:              "List, check availability and order OVH Eco (including kimsufi) servers.",
-	PersistentPreRunE: logLevel,
+	PersistentPreRunE: preRun,
 	SilenceUsage:      true,
 }
 
@@ -27,15 +39,27 @@ func init() {
 	flag.Bind(rootCmd)
 
 	// Subcommands
+	rootCmd.AddCommand(auth.Cmd)
+	rootCmd.AddCommand(cache.Cmd)
+	rootCmd.AddCommand(catalog.Cmd)
 	rootCmd.AddCommand(check.Cmd)
+	rootCmd.AddCommand(compareprices.Cmd)
+	rootCmd.AddCommand(history.Cmd)
 	rootCmd.AddCommand(order.Cmd)
+	rootCmd.AddCommand(orders.Cmd)
 	rootCmd.AddCommand(list.Cmd)
+	rootCmd.AddCommand(predict.Cmd)
+	rootCmd.AddCommand(stats.Cmd)
 	rootCmd.AddCommand(version.Cmd)
+	rootCmd.AddCommand(watch.Cmd)
 }
 
 // Execute is the main entry point for the CLI
+// Commands run with a context cancelled on interrupt or termination, which cancels their in-flight OVH API calls.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		os.Exit(1)
 	}
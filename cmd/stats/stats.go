@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	cmdhistory "github.com/TheoBrigitte/kimsufi-notifier/cmd/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/output"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Compute restock statistics",
+		Long:  "Compute restock statistics from the availability history\n\nrestock frequency, window durations, time of day and day of week heatmaps, and per datacenter breakdowns",
+		Example: `  kimsufi-notifier stats --plan-code 24ska01
+  kimsufi-notifier stats --plan-code 24ska01 --since 30d --timezone Europe/Paris
+  kimsufi-notifier stats --plan-code 24ska01 --output json`,
+		RunE: runner,
+	}
+
+	// Flags variables
+	datacenters  []string
+	historyFile  string
+	outputFormat string
+	planCode     string
+	since        string
+	until        string
+	timezone     string
+)
+
+// init registers all flags
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &planCode)
+	flag.BindDatacentersFlag(Cmd, &datacenters)
+	flag.BindHistoryFileFlag(Cmd, &historyFile)
+	flag.BindOutputFlag(Cmd, &outputFormat)
+	flag.BindTimeRangeFlags(Cmd, &since, &until)
+	flag.BindTimezoneFlag(Cmd, &timezone)
+}
+
+// weekdays lists days of the week starting on Monday, for display.
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// runner is the main function for the stats command
+func runner(cmd *cobra.Command, args []string) error {
+	err := output.Validate(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flag.TimezoneFlagName, err)
+	}
+
+	transitions, err := cmdhistory.LoadTransitions(cmd, historyFile, planCode, datacenters, since, until)
+	if err != nil {
+		return err
+	}
+	if len(transitions) == 0 {
+		return fmt.Errorf("no history found, record some with: kimsufi-notifier check --record")
+	}
+
+	s := history.NewStats(transitions, time.Now(), loc)
+
+	switch outputFormat {
+	case output.FormatJSON:
+		return output.WriteJSON(os.Stdout, s)
+	case output.FormatCSV:
+		return output.WriteCSV(os.Stdout, []string{"section", "key", "value"}, csvRows(s, loc))
+	}
+
+	return printTables(s, loc)
+}
+
+// printTables displays the statistics as a sequence of tables.
+func printTables(s history.Stats, loc *time.Location) error {
+	fmt.Println("# summary")
+	err := output.WriteTable(os.Stdout, []string{"metric", "value"}, summaryRows(s, loc))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n# datacenters")
+	var rows [][]string
+	for _, dc := range s.Datacenters {
+		rows = append(rows, []string{
+			dc.Datacenter,
+			strconv.Itoa(dc.Windows),
+			formatFloat(dc.RestocksPerDay),
+			cmdhistory.FormatDuration(dc.Durations.Median),
+			cmdhistory.FormatDuration(dc.Durations.P90),
+			dc.LastRestock.In(loc).Format(time.DateTime),
+		})
+	}
+	err = output.WriteTable(os.Stdout, []string{"datacenter", "windows", "restocks/day", "median", "p90", "lastRestock"}, rows)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n# restocks by day of week and hour of day (%s)\n", loc)
+	headers := []string{"day"}
+	for hour := range 24 {
+		headers = append(headers, fmt.Sprintf("%02d", hour))
+	}
+	headers = append(headers, "total")
+
+	rows = nil
+	for _, day := range weekdays {
+		row := []string{day.String()[:3]}
+		for hour := range 24 {
+			row = append(row, formatCount(s.Heatmap[day][hour]))
+		}
+		row = append(row, strconv.Itoa(s.DayOfWeek[day]))
+		rows = append(rows, row)
+	}
+
+	total := []string{"total"}
+	for hour := range 24 {
+		total = append(total, formatCount(s.HourOfDay[hour]))
+	}
+	total = append(total, strconv.Itoa(s.Windows))
+	rows = append(rows, total)
+
+	return output.WriteTable(os.Stdout, headers, rows)
+}
+
+// summaryRows returns the global statistics as metric, value rows.
+func summaryRows(s history.Stats, loc *time.Location) [][]string {
+	return [][]string{
+		{"from", s.From.In(loc).Format(time.DateTime)},
+		{"to", s.To.In(loc).Format(time.DateTime)},
+		{"windows", strconv.Itoa(s.Windows)},
+		{"restocks/day", formatFloat(s.RestocksPerDay)},
+		{"meanInterval", cmdhistory.FormatDuration(s.MeanInterval)},
+		{"minDuration", cmdhistory.FormatDuration(s.Durations.Min)},
+		{"meanDuration", cmdhistory.FormatDuration(s.Durations.Mean)},
+		{"medianDuration", cmdhistory.FormatDuration(s.Durations.Median)},
+		{"p75Duration", cmdhistory.FormatDuration(s.Durations.P75)},
+		{"p90Duration", cmdhistory.FormatDuration(s.Durations.P90)},
+		{"p95Duration", cmdhistory.FormatDuration(s.Durations.P95)},
+		{"maxDuration", cmdhistory.FormatDuration(s.Durations.Max)},
+	}
+}
+
+// csvRows returns all statistics as section, key, value rows.
+func csvRows(s history.Stats, loc *time.Location) [][]string {
+	var rows [][]string
+
+	for _, r := range summaryRows(s, loc) {
+		rows = append(rows, []string{"summary", r[0], r[1]})
+	}
+
+	for _, dc := range s.Datacenters {
+		section := "datacenter:" + dc.Datacenter
+		rows = append(rows,
+			[]string{section, "windows", strconv.Itoa(dc.Windows)},
+			[]string{section, "restocks/day", formatFloat(dc.RestocksPerDay)},
+			[]string{section, "medianDuration", cmdhistory.FormatDuration(dc.Durations.Median)},
+			[]string{section, "p90Duration", cmdhistory.FormatDuration(dc.Durations.P90)},
+			[]string{section, "lastRestock", dc.LastRestock.In(loc).Format(time.RFC3339)},
+		)
+	}
+
+	for hour, count := range s.HourOfDay {
+		rows = append(rows, []string{"hourOfDay", fmt.Sprintf("%02d", hour), strconv.Itoa(count)})
+	}
+
+	for _, day := range weekdays {
+		rows = append(rows, []string{"dayOfWeek", day.String(), strconv.Itoa(s.DayOfWeek[day])})
+	}
+
+	for _, day := range weekdays {
+		for hour := range 24 {
+			rows = append(rows, []string{"heatmap", fmt.Sprintf("%s %02d", day.String(), hour), strconv.Itoa(s.Heatmap[day][hour])})
+		}
+	}
+
+	return rows
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// formatCount displays zero counts as a dot to keep heatmaps readable.
+func formatCount(count int) string {
+	if count == 0 {
+		return "."
+	}
+
+	return strconv.Itoa(count)
+}
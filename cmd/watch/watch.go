@@ -0,0 +1,507 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/flag"
+	"github.com/TheoBrigitte/kimsufi-notifier/cmd/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/history"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderflow"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/orderstate"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Watch availability and order",
+		Long:  "Poll the availability of a plan, notify when it changes and optionally order it as soon as it is available\n\ndatacenters are watched, and ordered, in the given order of preference\n\nplaced orders are persisted in the orders file, the watcher stops once --max-orders orders were placed for the plan",
+		Example: `  kimsufi-notifier watch --plan-code 24ska01 --datacenters gra,rbx --notify-webhook https://example.com/hook
+  kimsufi-notifier watch --plan-code 24ska01 --datacenters gra,rbx --interval 1m --record
+  kimsufi-notifier watch --plan-code vps-2025-model2 --country US --endpoint ovh-us --datacenters US-WEST-OR --auto-order --item-option os=option-linux
+  kimsufi-notifier watch --candidate plan=24sk50,datacenters=rbx+gra+sbg --candidate plan=24ska01,datacenters=rbx+gra+sbg --auto-order`,
+		RunE: runner,
+	}
+
+	// Flags variables
+	autoOrder     bool
+	datacenters   []string
+	historyFile   string
+	interval      time.Duration
+	maxOrders     int
+	minInterval   time.Duration
+	orderParams   order.Params
+	ordersFile    string
+	planCode      string
+	prepareCart   bool
+	recordHistory bool
+	webhooks      []string
+)
+
+// init registers all flags
+func init() {
+	flag.BindPlanCodeFlag(Cmd, &planCode)
+	flag.BindDatacentersFlag(Cmd, &datacenters)
+	flag.BindHistoryFileFlag(Cmd, &historyFile)
+	flag.BindNotifyWebhookFlag(Cmd, &webhooks)
+	order.BindFlags(Cmd, &orderParams)
+
+	Cmd.PersistentFlags().BoolVar(&autoOrder, "auto-order", false, "order the plan as soon as it is available")
+	Cmd.PersistentFlags().DurationVar(&interval, "interval", 5*time.Minute, "time between availability checks")
+	Cmd.PersistentFlags().IntVar(&maxOrders, "max-orders", 1, "maximum number of orders placed for the plan, or for all the --candidate plans, including the ones already in the orders file")
+	Cmd.PersistentFlags().DurationVar(&minInterval, "min-interval", 0, "minimum time between availability checks, when set the interval is shortened down to this value when the history predicts a likely restock")
+	Cmd.PersistentFlags().BoolVar(&prepareCart, "prepare-cart", false, "keep a configured and assigned cart ready, so that only the datacenter is configured before checkout when the plan is available, requires --auto-order")
+	Cmd.PersistentFlags().StringVar(&ordersFile, "orders-file", "", "file recording the placed orders (default $XDG_DATA_HOME/kimsufi-notifier/orders.json)")
+	Cmd.PersistentFlags().BoolVar(&recordHistory, "record", false, "record availability changes in the history file")
+}
+
+// runner is the main function for the watch command
+func runner(cmd *cobra.Command, args []string) error {
+	candidates, err := orderflow.ParseCandidates(orderParams.Candidates)
+	if err != nil {
+		return err
+	}
+
+	// Flag validation
+	if planCode == "" && len(candidates) == 0 {
+		return fmt.Errorf("--%s or --candidate is required", flag.PlanCodeFlagName)
+	}
+	if planCode != "" && len(candidates) > 0 {
+		return fmt.Errorf("--%s and --candidate can not be used together", flag.PlanCodeFlagName)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if minInterval < 0 || minInterval > interval {
+		return fmt.Errorf("--min-interval must be between 0 and --interval")
+	}
+	if autoOrder && maxOrders <= 0 {
+		return fmt.Errorf("--max-orders must be positive")
+	}
+	if prepareCart && !autoOrder {
+		return fmt.Errorf("--prepare-cart requires --auto-order")
+	}
+	if prepareCart && len(candidates) > 0 {
+		return fmt.Errorf("--prepare-cart can not be used with --candidate")
+	}
+
+	// A single plan is watched as the only candidate.
+	useCandidates := len(candidates) > 0
+	if !useCandidates {
+		candidates = orderflow.Candidates{{PlanCode: planCode, Datacenters: datacenters}}
+	}
+	planCodes := candidates.PlanCodes()
+
+	endpoint := cmd.Flag(flag.OVHAPIEndpointFlagName).Value.String()
+	subsidiary := cmd.Flag(flag.CountryFlagName).Value.String()
+
+	// Initialize kimsufi service
+	k, err := kimsufi.NewService(endpoint, log.StandardLogger(), nil)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	k = k.WithContext(cmd.Context())
+
+	historyStore, err := history.NewStore(historyFile)
+	if err != nil {
+		return err
+	}
+
+	stateStore, err := orderstate.NewStore(ordersFile)
+	if err != nil {
+		return err
+	}
+
+	state, err := stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load orders: %w", err)
+	}
+
+	orders := state.Count(planCodes...)
+	if autoOrder {
+		if orders >= maxOrders {
+			last := state.Last(planCodes...)
+			fmt.Printf("> %s already ordered %d time(s), last %s in %s at %s, remove %s to order again\n", strings.Join(planCodes, ", "), orders, last.PlanCode, last.Datacenter, last.Time.Local().Format(time.DateTime), stateStore.Path())
+			return nil
+		}
+
+		// Fail early rather than when the plan becomes available.
+		if !orderParams.DryRun {
+			orderParams.Endpoint = endpoint
+			orderParams.Credentials, err = order.ReadCredentials(orderParams)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	n := notifier.New(os.Stdout, webhooks)
+
+	ctx := cmd.Context()
+
+	for _, c := range candidates {
+		fmt.Printf("> watching %s every %s\n", c, interval)
+	}
+
+	var previous history.Observations
+	var prepared *orderflow.Prepared
+	for {
+		now := time.Now()
+
+		if prepareCart {
+			prepared = warmCart(k, prepared, endpoint, subsidiary, now)
+		}
+
+		observations, err := observeAll(k, candidates, subsidiary)
+		if err != nil {
+			log.Errorf("failed to check availability: %v", err)
+		} else {
+			if recordHistory {
+				_, err := historyStore.Record(now, endpoint, observations)
+				if err != nil {
+					log.Errorf("failed to record history: %v", err)
+				}
+			}
+
+			events := availabilityEvents(now, endpoint, previous, observations)
+			err = notifier.NotifyAll(n, events)
+			if err != nil {
+				log.Warnf("failed to notify availability changes: %v", err)
+			}
+			previous = observations
+
+			evaluations := evaluate(candidates, observations)
+			if autoOrder && slices.ContainsFunc(evaluations, orderflow.Evaluation.IsAvailable) {
+				var results []*orderflow.Result
+				var err error
+				if useCandidates {
+					// The remaining orders, each of --quantity items.
+					total := (maxOrders - orders) * max(orderParams.Quantity, 1)
+					results, err = orderflow.OrderEvaluated(k, orderOptions(endpoint, subsidiary, nil), evaluations, total)
+				} else {
+					results, err = placeOrder(k, prepared, endpoint, subsidiary, evaluations[0].Available)
+					if prepared != nil && prepared.Done() {
+						prepared = nil
+					}
+				}
+
+				done, err := recordOrders(stateStore, n, endpoint, subsidiary, planCodes, results, err, &orders)
+				if err != nil {
+					return err
+				}
+				if done {
+					fmt.Printf("> %d order(s) placed for %s, stop watching\n", orders, strings.Join(planCodes, ", "))
+					return nil
+				}
+			}
+		}
+
+		wait := nextInterval(historyStore, endpoint, candidates, now)
+		log.Debugf("next check in %s", wait)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("> stop watching")
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// observeAll returns the availability of the plan of each candidate in its datacenters.
+func observeAll(k *kimsufi.Service, candidates orderflow.Candidates, subsidiary string) (history.Observations, error) {
+	var observations history.Observations
+	for _, planCode := range candidates.PlanCodes() {
+		o, err := observe(k, planCode, subsidiary, watchedDatacenters(candidates, planCode))
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, o...)
+	}
+
+	return observations, nil
+}
+
+// watchedDatacenters returns the datacenters of the candidates for planCode, or none when any of them accepts all datacenters.
+func watchedDatacenters(candidates orderflow.Candidates, planCode string) []string {
+	var dcs []string
+	for _, c := range candidates {
+		if c.PlanCode != planCode {
+			continue
+		}
+		if len(c.Datacenters) == 0 {
+			return nil
+		}
+		for _, dc := range c.Datacenters {
+			if !containsFold(dcs, dc) {
+				dcs = append(dcs, dc)
+			}
+		}
+	}
+
+	return dcs
+}
+
+// evaluate returns the availability of each candidate from the observations.
+func evaluate(candidates orderflow.Candidates, observations history.Observations) orderflow.Evaluations {
+	var evaluations orderflow.Evaluations
+	for i, c := range candidates {
+		var available []string
+		for _, o := range observations {
+			if o.PlanCode == c.PlanCode && o.Status == kimsufiavailability.StatusAvailable {
+				available = append(available, o.Datacenter)
+			}
+		}
+
+		evaluations = append(evaluations, orderflow.Evaluation{
+			Rank:      i + 1,
+			Candidate: c,
+			Available: c.Preferred(available),
+		})
+	}
+
+	return evaluations
+}
+
+// observe returns the availability of planCode in each of the datacenters, or in all datacenters when none is given.
+func observe(k *kimsufi.Service, planCode, subsidiary string, datacenters []string) (history.Observations, error) {
+	var observations history.Observations
+
+	if kimsufi.IsVPSPlanCode(planCode) {
+		availabilities, err := k.GetVPSAvailabilities(planCode, subsidiary, "")
+		if err != nil {
+			return nil, err
+		}
+
+		observations = history.NewObservationsFromVPSAvailabilities(planCode, *availabilities)
+	} else {
+		availabilities, err := k.GetAvailabilities(datacenters, planCode, nil)
+		if err != nil {
+			if kimsufi.IsAvailabilityNotFoundError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		observations = history.NewObservationsFromAvailabilities(*availabilities)
+	}
+
+	if len(datacenters) == 0 {
+		return observations, nil
+	}
+
+	return slices.DeleteFunc(observations, func(o history.Observation) bool {
+		return !containsFold(datacenters, o.Datacenter)
+	}), nil
+}
+
+// availabilityEvents returns an event for each datacenter whose status changed since the previous observations.
+// Unavailable datacenters are not reported on the first check.
+func availabilityEvents(now time.Time, endpoint string, previous, current history.Observations) []notifier.Event {
+	status := map[string]string{}
+	for _, o := range previous {
+		status[o.PlanCode+"/"+o.Datacenter] = o.Status
+	}
+
+	var events []notifier.Event
+	for _, o := range current {
+		before, found := status[o.PlanCode+"/"+o.Datacenter]
+		if before == o.Status || (!found && o.Status != kimsufiavailability.StatusAvailable) {
+			continue
+		}
+
+		t := history.Transition{
+			Time:       now.UTC(),
+			Endpoint:   endpoint,
+			PlanCode:   o.PlanCode,
+			Datacenter: o.Datacenter,
+			Status:     o.Status,
+		}
+
+		e := notifier.Event{
+			Time:  now,
+			Type:  history.EventTypeUnavailable,
+			Title: fmt.Sprintf("%s is no longer available in %s", o.PlanCode, o.Datacenter),
+			Data:  t,
+		}
+		if t.IsAvailable() {
+			e.Type = history.EventTypeAvailable
+			e.Title = fmt.Sprintf("%s is available in %s", o.PlanCode, o.Datacenter)
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+// orderOptions returns the order options for the datacenters.
+// Without a Configure function, missing item configurations fail the order instead of prompting.
+func orderOptions(endpoint, subsidiary string, datacenters []string) orderflow.Options {
+	o := orderParams.OrderOptions()
+	o.Endpoint = endpoint
+	o.Subsidiary = subsidiary
+	o.PlanCode = planCode
+	o.Datacenters = datacenters
+	o.Progress = order.Progress
+
+	return o
+}
+
+// warmCart returns a cart ready to be checked out, preparing a new one when there is none
+// or when the prepared one could not be refreshed before it expires.
+func warmCart(k *kimsufi.Service, prepared *orderflow.Prepared, endpoint, subsidiary string, now time.Time) *orderflow.Prepared {
+	if prepared != nil {
+		if !prepared.NeedsRefresh(now) {
+			return prepared
+		}
+
+		err := prepared.Refresh(now)
+		if err == nil {
+			return prepared
+		}
+		log.Warnf("failed to refresh cart %s, preparing a new one: %v", prepared.CartID, err)
+	}
+
+	// The datacenter is configured on checkout, from the available ones.
+	dcs := datacenters
+	if len(dcs) == 0 {
+		dcs = []string{orderflow.AnyDatacenter}
+	}
+
+	fmt.Printf("> preparing cart for %s\n", planCode)
+	prepared, err := orderflow.Prepare(k, orderOptions(endpoint, subsidiary, dcs))
+	if err != nil {
+		log.Errorf("failed to prepare cart for %s: %v", planCode, err)
+		return nil
+	}
+
+	return prepared
+}
+
+// placeOrder checks out the prepared cart, or runs the order workflow when there is none, in the available datacenters.
+func placeOrder(k *kimsufi.Service, prepared *orderflow.Prepared, endpoint, subsidiary string, available []string) ([]*orderflow.Result, error) {
+	fmt.Printf("> ordering %s in %s\n", planCode, strings.Join(available, ", "))
+
+	var result *orderflow.Result
+	var err error
+	if prepared != nil {
+		result, err = prepared.Checkout(available)
+	} else {
+		result, err = orderflow.Order(k, orderOptions(endpoint, subsidiary, available))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []*orderflow.Result{result}, nil
+}
+
+// recordOrders records and notifies the placed orders, then notifies the order failure when err is set.
+// The results placed before a failure are recorded as well, so that they count towards the maximum.
+// It returns true once the maximum number of orders is reached.
+func recordOrders(store *orderstate.Store, n notifier.Notifier, endpoint, subsidiary string, planCodes []string, results []*orderflow.Result, err error, orders *int) (bool, error) {
+	for _, result := range results {
+		if result == nil || !result.DryRun && !result.Completed() {
+			continue
+		}
+
+		if result.DryRun {
+			// Dry-run orders count towards the maximum but are not persisted.
+			*orders++
+			continue
+		}
+
+		placed := orderstate.Order{
+			Time:       time.Now().UTC(),
+			Endpoint:   endpoint,
+			Subsidiary: subsidiary,
+			PlanCode:   result.PlanCode,
+			Datacenter: result.Datacenter,
+			OrderID:    result.Checkout.OrderID,
+			URL:        result.Checkout.URL,
+		}
+
+		// Persist the order before anything else, it must not be placed twice.
+		_, recordErr := store.Add(placed)
+		if recordErr != nil {
+			return true, fmt.Errorf("order placed but failed to record it in %s, stop watching to prevent duplicate orders: %w", store.Path(), recordErr)
+		}
+		*orders++
+
+		message := result.Checkout.URL
+		if result.Reason != "" {
+			message = fmt.Sprintf("%s\n%s", result.Reason, message)
+		}
+
+		e := notifier.Event{
+			Time:    time.Now(),
+			Type:    orderstate.EventTypeOrderCompleted,
+			Title:   fmt.Sprintf("Ordered %s in %s", result.PlanCode, result.Datacenter),
+			Message: message,
+			Data:    placed,
+		}
+		notifyErr := n.Notify(e)
+		if notifyErr != nil {
+			log.Warnf("failed to notify order: %v", notifyErr)
+		}
+	}
+
+	if err != nil {
+		e := notifier.Event{
+			Time:    time.Now(),
+			Type:    orderstate.EventTypeOrderFailed,
+			Title:   fmt.Sprintf("Failed to order %s", strings.Join(planCodes, ", ")),
+			Message: err.Error(),
+		}
+		notifyErr := n.Notify(e)
+		if notifyErr != nil {
+			log.Warnf("failed to notify order failure: %v", notifyErr)
+		}
+	}
+
+	return *orders >= maxOrders, nil
+}
+
+// nextInterval returns the time to wait before the next check.
+// When --min-interval is set, the interval is scaled down by the restock probability predicted from the history.
+func nextInterval(store *history.Store, endpoint string, candidates orderflow.Candidates, now time.Time) time.Duration {
+	if minInterval <= 0 {
+		return interval
+	}
+
+	transitions, err := store.Load()
+	if err != nil {
+		log.Warnf("failed to load history: %v", err)
+		return interval
+	}
+
+	// Poll as often as the most likely restock requires.
+	wait := interval
+	for _, c := range candidates {
+		t := transitions.Filter(history.Filter{Endpoint: endpoint, PlanCode: c.PlanCode, Datacenters: c.Datacenters})
+		if len(t) == 0 {
+			continue
+		}
+
+		predictions := history.Predict(t, now, interval, time.Local)
+		wait = min(wait, history.PollInterval(predictions[0].Probability, minInterval, interval))
+	}
+
+	return wait
+}
+
+// containsFold returns true if values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
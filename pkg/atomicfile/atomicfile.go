@@ -0,0 +1,36 @@
+// Package atomicfile replaces files at once, so that they are never read partially written.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFile replaces the content of the file at path with data and sets its permissions to perm.
+// The data is written to a temporary file next to path, which is then renamed over it.
+// The directory of path must exist.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,232 @@
+package catalogdiff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+)
+
+// Compare returns the differences from the old snapshot to the new one.
+func Compare(old, new Snapshot) Diff {
+	d := Diff{
+		Endpoint:     new.Endpoint,
+		Subsidiary:   new.Subsidiary,
+		Kind:         new.Kind,
+		CurrencyCode: new.CurrencyCode,
+		From:         old.Time,
+		To:           new.Time,
+	}
+
+	for _, plan := range new.Plans {
+		oldPlan := old.GetPlan(plan.PlanCode)
+		if oldPlan == nil {
+			d.Added = append(d.Added, plan)
+			continue
+		}
+
+		changes := comparePlans(*oldPlan, plan, new.CurrencyCode)
+		if len(changes) > 0 {
+			d.Changed = append(d.Changed, PlanChange{
+				PlanCode:    plan.PlanCode,
+				InvoiceName: plan.InvoiceName,
+				Changes:     changes,
+			})
+		}
+	}
+
+	for _, plan := range old.Plans {
+		if new.GetPlan(plan.PlanCode) == nil {
+			d.Removed = append(d.Removed, plan)
+		}
+	}
+
+	return d
+}
+
+// comparePlans returns the changes between two versions of a plan.
+func comparePlans(old, new Plan, currencyCode string) []Change {
+	var changes []Change
+
+	if old.InvoiceName != new.InvoiceName {
+		changes = append(changes, Change{Field: "invoiceName", Old: old.InvoiceName, New: new.InvoiceName})
+	}
+
+	// Pricings
+	oldPricings := pricingsByKey(old.Pricings)
+	newPricings := pricingsByKey(new.Pricings)
+	for _, key := range sortedKeys(oldPricings, newPricings) {
+		o, inOld := oldPricings[key]
+		n, inNew := newPricings[key]
+		field := "pricing " + key
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Field: field, New: FormatPrice(n.GetPrice(), currencyCode)})
+		case !inNew:
+			changes = append(changes, Change{Field: field, Old: FormatPrice(o.GetPrice(), currencyCode)})
+		case o.Price != n.Price:
+			changes = append(changes, Change{Field: field, Old: FormatPrice(o.GetPrice(), currencyCode), New: FormatPrice(n.GetPrice(), currencyCode)})
+		}
+	}
+
+	// Addon families
+	oldFamilies := familiesByName(old.AddonFamilies)
+	newFamilies := familiesByName(new.AddonFamilies)
+	for _, name := range sortedKeys(oldFamilies, newFamilies) {
+		o, inOld := oldFamilies[name]
+		n, inNew := newFamilies[name]
+		field := "addonFamily " + name
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Field: field, New: strings.Join(n.Addons, ", ")})
+		case !inNew:
+			changes = append(changes, Change{Field: field, Old: strings.Join(o.Addons, ", ")})
+		default:
+			if o.Default != n.Default {
+				changes = append(changes, Change{Field: field + " default", Old: o.Default, New: n.Default})
+			}
+			if o.Mandatory != n.Mandatory {
+				changes = append(changes, Change{Field: field + " mandatory", Old: strconv.FormatBool(o.Mandatory), New: strconv.FormatBool(n.Mandatory)})
+			}
+			removed, added := compareValues(o.Addons, n.Addons)
+			if len(removed) > 0 || len(added) > 0 {
+				changes = append(changes, Change{Field: field + " addons", Old: strings.Join(removed, ", "), New: strings.Join(added, ", ")})
+			}
+		}
+	}
+
+	// Datacenters
+	removed, added := compareValues(old.Datacenters, new.Datacenters)
+	if len(removed) > 0 || len(added) > 0 {
+		changes = append(changes, Change{Field: "datacenters", Old: strings.Join(removed, ", "), New: strings.Join(added, ", ")})
+	}
+
+	return changes
+}
+
+// IsEmpty returns true when both snapshots are equivalent.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Events returns one notification event per added, removed or changed plan.
+func (d Diff) Events(now time.Time) []notifier.Event {
+	var events []notifier.Event
+	where := fmt.Sprintf("%s %s catalog (%s)", d.Subsidiary, d.Kind, d.Endpoint)
+
+	for _, plan := range d.Added {
+		message := plan.InvoiceName
+		if plan.Price > 0 {
+			message += ", " + FormatPrice(plan.Price, d.CurrencyCode)
+		}
+		if len(plan.Datacenters) > 0 {
+			message += ", " + strings.Join(plan.Datacenters, ", ")
+		}
+
+		events = append(events, notifier.Event{
+			Time:    now,
+			Type:    EventTypePlanAdded,
+			Title:   fmt.Sprintf("New plan %s in %s", plan.PlanCode, where),
+			Message: message,
+			Data:    plan,
+		})
+	}
+
+	for _, plan := range d.Removed {
+		events = append(events, notifier.Event{
+			Time:    now,
+			Type:    EventTypePlanRemoved,
+			Title:   fmt.Sprintf("Plan %s removed from %s", plan.PlanCode, where),
+			Message: plan.InvoiceName,
+			Data:    plan,
+		})
+	}
+
+	for _, change := range d.Changed {
+		var lines []string
+		for _, c := range change.Changes {
+			lines = append(lines, c.String())
+		}
+
+		events = append(events, notifier.Event{
+			Time:    now,
+			Type:    EventTypePlanChanged,
+			Title:   fmt.Sprintf("Plan %s changed in %s", change.PlanCode, where),
+			Message: strings.Join(lines, "\n"),
+			Data:    change,
+		})
+	}
+
+	return events
+}
+
+// String returns a human readable representation of the change.
+func (c Change) String() string {
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("%s: added %s", c.Field, c.New)
+	case c.New == "":
+		return fmt.Sprintf("%s: removed %s", c.Field, c.Old)
+	}
+
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// compareValues returns the values removed from old and the values added in new.
+func compareValues(old, new []string) ([]string, []string) {
+	var removed, added []string
+
+	for _, v := range old {
+		if !slices.Contains(new, v) {
+			removed = append(removed, v)
+		}
+	}
+
+	for _, v := range new {
+		if !slices.Contains(old, v) {
+			added = append(added, v)
+		}
+	}
+
+	return removed, added
+}
+
+func pricingsByKey(pricings []Pricing) map[string]Pricing {
+	m := map[string]Pricing{}
+	for _, p := range pricings {
+		m[p.Key()] = p
+	}
+
+	return m
+}
+
+func familiesByName(families []AddonFamily) map[string]AddonFamily {
+	m := map[string]AddonFamily{}
+	for _, f := range families {
+		m[f.Name] = f
+	}
+
+	return m
+}
+
+// sortedKeys returns the sorted union of the keys of both maps.
+func sortedKeys[T any](a, b map[string]T) []string {
+	var keys []string
+
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, found := a[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,98 @@
+package catalogdiff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	monthly := func(price int) Pricing {
+		return Pricing{Capacities: []string{"installation", "renew"}, Interval: 1, IntervalUnit: "month", Mode: "default", Price: price, Type: "rental"}
+	}
+	plan := func(planCode string, price int, datacenters ...string) Plan {
+		return Plan{
+			PlanCode:      planCode,
+			InvoiceName:   "KS-" + planCode,
+			Pricings:      []Pricing{monthly(price)},
+			AddonFamilies: []AddonFamily{{Name: "memory", Default: "ram-32g", Mandatory: true, Addons: []string{"ram-32g"}}},
+			Datacenters:   datacenters,
+		}
+	}
+
+	t0 := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	snapshot := func(plans ...Plan) Snapshot {
+		return Snapshot{Time: t0, Endpoint: "ovh-eu", Subsidiary: "FR", Kind: KindEco, CurrencyCode: "EUR", Plans: plans}
+	}
+
+	upgraded := plan("a", 1000000000, "gra")
+	upgraded.AddonFamilies[0].Addons = append(upgraded.AddonFamilies[0].Addons, "ram-64g")
+
+	testCases := []struct {
+		name     string
+		old      Snapshot
+		new      Snapshot
+		expected Diff
+	}{
+		{
+			name: "unchanged",
+			old:  snapshot(plan("a", 1000000000, "gra")),
+			new:  snapshot(plan("a", 1000000000, "gra")),
+		},
+		{
+			name: "added and removed",
+			old:  snapshot(plan("a", 1000000000, "gra")),
+			new:  snapshot(plan("b", 2000000000, "rbx")),
+			expected: Diff{
+				Added:   []Plan{plan("b", 2000000000, "rbx")},
+				Removed: []Plan{plan("a", 1000000000, "gra")},
+			},
+		},
+		{
+			name: "price and datacenters",
+			old:  snapshot(plan("a", 1000000000, "gra")),
+			new:  snapshot(plan("a", 1200000000, "gra", "rbx")),
+			expected: Diff{
+				Changed: []PlanChange{{
+					PlanCode:    "a",
+					InvoiceName: "KS-a",
+					Changes: []Change{
+						{Field: "pricing default P1M rental installation,renew phase 0 commitment 0", Old: "10.00 EUR", New: "12.00 EUR"},
+						{Field: "datacenters", New: "rbx"},
+					},
+				}},
+			},
+		},
+		{
+			name: "addons",
+			old:  snapshot(plan("a", 1000000000, "gra")),
+			new:  snapshot(upgraded),
+			expected: Diff{
+				Changed: []PlanChange{{
+					PlanCode:    "a",
+					InvoiceName: "KS-a",
+					Changes: []Change{
+						{Field: "addonFamily memory addons", New: "ram-64g"},
+					},
+				}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.Endpoint = "ovh-eu"
+			tc.expected.Subsidiary = "FR"
+			tc.expected.Kind = KindEco
+			tc.expected.CurrencyCode = "EUR"
+			tc.expected.From = t0
+			tc.expected.To = t0
+
+			got := Compare(tc.old, tc.new)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Compare() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package catalogdiff
+
+import "time"
+
+const (
+	EventTypePlanAdded   = "catalog.plan.added"
+	EventTypePlanRemoved = "catalog.plan.removed"
+	EventTypePlanChanged = "catalog.plan.changed"
+)
+
+// Diff is the difference between two snapshots of the same catalog.
+type Diff struct {
+	Endpoint     string    `json:"endpoint"`
+	Subsidiary   string    `json:"subsidiary"`
+	Kind         string    `json:"kind"`
+	CurrencyCode string    `json:"currencyCode"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+
+	Added   []Plan       `json:"added,omitempty"`
+	Removed []Plan       `json:"removed,omitempty"`
+	Changed []PlanChange `json:"changed,omitempty"`
+}
+
+// PlanChange lists the changes of a plan present in both snapshots.
+type PlanChange struct {
+	PlanCode    string   `json:"planCode"`
+	InvoiceName string   `json:"invoiceName"`
+	Changes     []Change `json:"changes"`
+}
+
+// Change is a single field change, Old is empty for additions and New is empty for removals.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
@@ -0,0 +1,166 @@
+package catalogdiff
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+var (
+	// priceDivider converts catalog prices to their value, see kimsuficatalog.PlanPricing.GetPrice.
+	priceDivider = math.Pow10(8)
+)
+
+// NewSnapshotFromCatalog creates a new Snapshot from an Eco catalog.
+func NewSnapshotFromCatalog(endpoint string, now time.Time, c kimsuficatalog.Catalog) Snapshot {
+	s := Snapshot{
+		Time:         now,
+		Endpoint:     endpoint,
+		Subsidiary:   c.Locale.Subsidiary,
+		Kind:         KindEco,
+		CurrencyCode: c.Locale.CurrencyCode,
+	}
+
+	for _, p := range c.Plans {
+		plan := Plan{
+			PlanCode:    p.PlanCode,
+			InvoiceName: p.InvoiceName,
+			Price:       p.GetFirstPrice().GetPrice(),
+		}
+
+		for _, price := range p.Pricings {
+			plan.Pricings = append(plan.Pricings, Pricing{
+				Capacities:   price.Capacities,
+				Commitment:   price.Commitement,
+				Interval:     price.Interval,
+				IntervalUnit: price.IntervalUnit,
+				Mode:         price.Mode,
+				Phase:        price.Phase,
+				Price:        price.Price,
+				Type:         price.Type,
+			})
+		}
+
+		for _, family := range p.AddonFamilies {
+			plan.AddonFamilies = append(plan.AddonFamilies, AddonFamily{
+				Name:      family.Name,
+				Default:   family.Default,
+				Mandatory: family.Mandatory,
+				Addons:    family.Addons,
+			})
+		}
+
+		datacenters := p.GetConfiguration(kimsufiorder.ConfigurationLabelDatacenter)
+		if datacenters != nil {
+			plan.Datacenters = datacenters.Values
+		}
+
+		s.Plans = append(s.Plans, plan)
+	}
+
+	return s
+}
+
+// NewSnapshotFromVPSCatalog creates a new Snapshot from a VPS catalog.
+func NewSnapshotFromVPSCatalog(endpoint string, now time.Time, c kimsuficatalog.VPSCatalog) Snapshot {
+	s := Snapshot{
+		Time:         now,
+		Endpoint:     endpoint,
+		Subsidiary:   c.Locale.Subsidiary,
+		Kind:         KindVPS,
+		CurrencyCode: c.Locale.CurrencyCode,
+	}
+
+	for _, p := range c.Plans {
+		plan := Plan{
+			PlanCode:    p.PlanCode,
+			InvoiceName: p.InvoiceName,
+			Price:       p.GetFirstPrice().GetPrice(),
+		}
+
+		for _, price := range p.Pricings {
+			plan.Pricings = append(plan.Pricings, Pricing{
+				Capacities:   price.Capacities,
+				Commitment:   price.Commitment,
+				Interval:     price.Interval,
+				IntervalUnit: price.IntervalUnit,
+				Mode:         price.Mode,
+				Phase:        price.Phase,
+				Price:        price.Price,
+				Type:         price.Type,
+			})
+		}
+
+		for _, family := range p.AddonFamilies {
+			plan.AddonFamilies = append(plan.AddonFamilies, AddonFamily{
+				Name:      family.Name,
+				Default:   family.Default,
+				Mandatory: family.Mandatory,
+				Addons:    family.Addons,
+			})
+		}
+
+		datacenters := p.GetConfiguration(kimsufiorder.ConfigurationLabelVPSDatacenter)
+		if datacenters != nil {
+			plan.Datacenters = datacenters.Values
+		}
+
+		s.Plans = append(s.Plans, plan)
+	}
+
+	return s
+}
+
+// GetPlan returns the plan with the given plan code.
+func (s Snapshot) GetPlan(planCode string) *Plan {
+	for _, plan := range s.Plans {
+		if plan.PlanCode == planCode {
+			return &plan
+		}
+	}
+
+	return nil
+}
+
+// GetAddonFamily returns the addon family with the given name.
+func (p Plan) GetAddonFamily(name string) *AddonFamily {
+	for _, family := range p.AddonFamilies {
+		if family.Name == name {
+			return &family
+		}
+	}
+
+	return nil
+}
+
+// Key identifies a pricing within a plan.
+// e.g. default P1M rental renew phase 1 commitment 0
+func (p Pricing) Key() string {
+	capacities := slices.Clone(p.Capacities)
+	slices.Sort(capacities)
+
+	return fmt.Sprintf("%s %s %s %s phase %d commitment %d",
+		p.Mode,
+		kimsufi.IntervalToDuration(p.Interval, p.IntervalUnit),
+		p.Type,
+		strings.Join(capacities, ","),
+		p.Phase,
+		p.Commitment,
+	)
+}
+
+// GetPrice returns the human readable price as a float64.
+func (p Pricing) GetPrice() float64 {
+	return float64(p.Price) / priceDivider
+}
+
+// FormatPrice returns the price followed by the currency code.
+func FormatPrice(price float64, currencyCode string) string {
+	return fmt.Sprintf("%.2f %s", price, currencyCode)
+}
@@ -0,0 +1,55 @@
+package catalogdiff
+
+import "time"
+
+const (
+	KindEco = "eco"
+	KindVPS = "vps"
+)
+
+var (
+	// Kinds is the list of supported catalog kinds.
+	Kinds = []string{KindEco, KindVPS}
+)
+
+// Snapshot is the normalized content of an Eco or VPS catalog,
+// reduced to the fields which are compared between two catalogs.
+type Snapshot struct {
+	Time         time.Time `json:"time"`
+	Endpoint     string    `json:"endpoint"`
+	Subsidiary   string    `json:"subsidiary"`
+	Kind         string    `json:"kind"`
+	CurrencyCode string    `json:"currencyCode"`
+	Plans        []Plan    `json:"plans"`
+}
+
+// Plan is the normalized content of a catalog plan.
+type Plan struct {
+	PlanCode    string `json:"planCode"`
+	InvoiceName string `json:"invoiceName"`
+	// Price is the listed price of the plan, see kimsuficatalog.Plan.GetFirstPrice.
+	Price         float64       `json:"price,omitempty"`
+	Pricings      []Pricing     `json:"pricings"`
+	AddonFamilies []AddonFamily `json:"addonFamilies"`
+	Datacenters   []string      `json:"datacenters"`
+}
+
+// Pricing is the normalized content of a plan pricing.
+type Pricing struct {
+	Capacities   []string `json:"capacities"`
+	Commitment   int      `json:"commitment"`
+	Interval     int      `json:"interval"`
+	IntervalUnit string   `json:"intervalUnit"`
+	Mode         string   `json:"mode"`
+	Phase        int      `json:"phase"`
+	Price        int      `json:"price"`
+	Type         string   `json:"type"`
+}
+
+// AddonFamily is the normalized content of a plan addon family.
+type AddonFamily struct {
+	Name      string   `json:"name"`
+	Default   string   `json:"default"`
+	Mandatory bool     `json:"mandatory"`
+	Addons    []string `json:"addons"`
+}
@@ -0,0 +1,165 @@
+package catalogdiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/atomicfile"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+const (
+	// DirName is the default snapshots directory name inside the data directory.
+	DirName = "catalogs"
+
+	previousSuffix = ".previous"
+)
+
+// Store persists catalog snapshots per endpoint, subsidiary and kind.
+// The last catalog is kept along with the one before it.
+type Store struct {
+	dir string
+}
+
+// storedCatalog is the file content of a stored catalog.
+type storedCatalog struct {
+	Time    time.Time       `json:"time"`
+	Catalog json.RawMessage `json:"catalog"`
+}
+
+// NewStore creates a new Store in dir.
+// If dir is empty, the default snapshots directory is used.
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		d, err := xdg.DataFile(DirName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find snapshots directory: %w", err)
+		}
+		dir = d
+	}
+
+	s := &Store{
+		dir: dir,
+	}
+
+	return s, nil
+}
+
+// Dir returns the snapshots directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save stores the raw catalog, the catalog saved before is kept as the previous one.
+// Both files are written next to their path and renamed over it, so that they are never left partially written.
+func (s *Store) Save(endpoint, subsidiary, kind string, now time.Time, catalog any) error {
+	raw, err := json.Marshal(catalog)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(storedCatalog{Time: now.UTC(), Catalog: raw})
+	if err != nil {
+		return err
+	}
+
+	path := s.path(endpoint, subsidiary, kind, "")
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	previous, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		err = atomicfile.WriteFile(s.path(endpoint, subsidiary, kind, previousSuffix), previous, 0o644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return atomicfile.WriteFile(path, data, 0o644)
+}
+
+// Load reads the last saved raw catalog into catalog.
+// It returns the time the catalog was saved at and false when no catalog was saved yet.
+func (s *Store) Load(endpoint, subsidiary, kind string, catalog any) (time.Time, bool, error) {
+	return s.load(s.path(endpoint, subsidiary, kind, ""), catalog)
+}
+
+// LoadPrevious reads the raw catalog saved before the last one into catalog.
+func (s *Store) LoadPrevious(endpoint, subsidiary, kind string, catalog any) (time.Time, bool, error) {
+	return s.load(s.path(endpoint, subsidiary, kind, previousSuffix), catalog)
+}
+
+// LoadSnapshot returns the snapshot of the last saved catalog, or nil when none was saved yet.
+func (s *Store) LoadSnapshot(endpoint, subsidiary, kind string) (*Snapshot, error) {
+	return s.loadSnapshot(endpoint, subsidiary, kind, s.Load)
+}
+
+// LoadPreviousSnapshot returns the snapshot of the catalog saved before the last one, or nil when none was saved yet.
+func (s *Store) LoadPreviousSnapshot(endpoint, subsidiary, kind string) (*Snapshot, error) {
+	return s.loadSnapshot(endpoint, subsidiary, kind, s.LoadPrevious)
+}
+
+func (s *Store) loadSnapshot(endpoint, subsidiary, kind string, load func(string, string, string, any) (time.Time, bool, error)) (*Snapshot, error) {
+	var snapshot Snapshot
+
+	switch kind {
+	case KindEco:
+		var c kimsuficatalog.Catalog
+		t, found, err := load(endpoint, subsidiary, kind, &c)
+		if err != nil || !found {
+			return nil, err
+		}
+		snapshot = NewSnapshotFromCatalog(endpoint, t, c)
+	case KindVPS:
+		var c kimsuficatalog.VPSCatalog
+		t, found, err := load(endpoint, subsidiary, kind, &c)
+		if err != nil || !found {
+			return nil, err
+		}
+		snapshot = NewSnapshotFromVPSCatalog(endpoint, t, c)
+	default:
+		return nil, fmt.Errorf("invalid catalog kind %q (allowed values: %s)", kind, strings.Join(Kinds, ", "))
+	}
+
+	return &snapshot, nil
+}
+
+func (s *Store) load(path string, catalog any) (time.Time, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var stored storedCatalog
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	err = json.Unmarshal(stored.Catalog, catalog)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return stored.Time, true, nil
+}
+
+// path returns the file path of a stored catalog.
+func (s *Store) path(endpoint, subsidiary, kind, suffix string) string {
+	return filepath.Join(s.dir, endpoint, strings.ToUpper(subsidiary), kind+suffix+".json")
+}
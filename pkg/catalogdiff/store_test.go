@@ -0,0 +1,47 @@
+package catalogdiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreSave(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	err = s.Save("ovh-eu", "FR", KindEco, first, []string{"24ska01"})
+	if err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	err = s.Save("ovh-eu", "FR", KindEco, second, []string{"24ska01", "24sk10"})
+	if err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	var catalog []string
+	saved, found, err := s.Load("ovh-eu", "FR", KindEco, &catalog)
+	if err != nil || !found || !saved.Equal(second) || len(catalog) != 2 {
+		t.Errorf("Load() = %s, %t, %v, %v, want the second catalog", saved, found, err, catalog)
+	}
+
+	saved, found, err = s.LoadPrevious("ovh-eu", "FR", KindEco, &catalog)
+	if err != nil || !found || !saved.Equal(first) || len(catalog) != 1 {
+		t.Errorf("LoadPrevious() = %s, %t, %v, %v, want the first catalog", saved, found, err, catalog)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "ovh-eu", "FR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Save() left %d files, want 2", len(entries))
+	}
+}
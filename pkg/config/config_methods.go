@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Load reads the configuration file at path, the default configuration file when path is empty.
+// A missing default configuration file is not an error and returns an empty configuration.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		p, err := xdg.ConfigFile(FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find configuration file: %w", err)
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Parse parses a configuration from YAML, unknown fields are an error.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(c)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Profile returns the profile with the given name, or the default profile when name is empty.
+// It returns nil when name is empty and there is no default profile.
+func (c Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found (known profiles: %s)", name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+
+	return &p, nil
+}
+
+// Values returns the flag values of the command, command values override the values of every command.
+func (p Profile) Values(command string) (map[string][]string, error) {
+	values := make(map[string][]string)
+	if p.Endpoint != "" {
+		values["endpoint"] = []string{p.Endpoint}
+	}
+	if p.Country != "" {
+		values["country"] = []string{p.Country}
+	}
+	if len(p.NotifyWebhooks) > 0 {
+		values["notify-webhook"] = p.NotifyWebhooks
+	}
+
+	for _, v := range []Values{p.Flags, p.Commands[command]} {
+		for name, value := range v {
+			s, err := toStrings(value)
+			if err != nil {
+				return nil, fmt.Errorf("flag %s: %w", name, err)
+			}
+			values[name] = s
+		}
+	}
+
+	return values, nil
+}
+
+// EnvVarName returns the name of the environment variable setting the flag value, e.g. KIMSUFI_NOTIFIER_PLAN_CODE for plan-code.
+func EnvVarName(flagName string) string {
+	return EnvVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// toStrings returns the value as flag values, maps are returned as sorted key=value.
+func toStrings(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		var values []string
+		for _, item := range v {
+			s, err := scalar(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	case Values:
+		return toStrings(map[string]any(v))
+	case map[string]any:
+		var values []string
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			s, err := scalar(v[key])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, fmt.Sprintf("%s=%s", key, s))
+		}
+		return values, nil
+	default:
+		s, err := scalar(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+}
+
+func scalar(value any) (string, error) {
+	switch v := value.(type) {
+	case string, bool, int, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v, expected a string, a number or a boolean", v)
+	}
+}
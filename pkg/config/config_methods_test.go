@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProfileValues(t *testing.T) {
+	data := `
+defaultProfile: eu-kimsufi
+profiles:
+  eu-kimsufi:
+    endpoint: ovh-eu
+    country: FR
+    notifyWebhooks: [https://example.com/hook]
+    flags:
+      datacenters: [gra, rbx]
+    commands:
+      watch:
+        plan-code: 24ska01
+        interval: 5m
+        auto-order: true
+        datacenters: [rbx]
+      order:
+        item-configuration:
+          region: europe
+        max-monthly-price: 15.5
+  us-vps:
+    endpoint: ovh-us
+    country: US
+`
+
+	c, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	p, err := c.Profile("")
+	if err != nil {
+		t.Fatalf("Profile() failed: %v", err)
+	}
+
+	testCases := []struct {
+		command  string
+		expected map[string][]string
+	}{
+		{
+			command: "watch",
+			expected: map[string][]string{
+				"endpoint":       {"ovh-eu"},
+				"country":        {"FR"},
+				"notify-webhook": {"https://example.com/hook"},
+				"datacenters":    {"rbx"},
+				"plan-code":      {"24ska01"},
+				"interval":       {"5m"},
+				"auto-order":     {"true"},
+			},
+		},
+		{
+			command: "order",
+			expected: map[string][]string{
+				"endpoint":           {"ovh-eu"},
+				"country":            {"FR"},
+				"notify-webhook":     {"https://example.com/hook"},
+				"datacenters":        {"gra", "rbx"},
+				"item-configuration": {"region=europe"},
+				"max-monthly-price":  {"15.5"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.command, func(t *testing.T) {
+			got, err := p.Values(tc.command)
+			if err != nil {
+				t.Fatalf("Values() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Values() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	_, err = c.Profile("unknown")
+	if err == nil {
+		t.Errorf("Profile(unknown) succeeded, want error")
+	}
+
+	_, err = Parse([]byte("profile:\n  eu: {}\n"))
+	if err == nil {
+		t.Errorf("Parse() of an unknown field succeeded, want error")
+	}
+}
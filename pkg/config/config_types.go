@@ -0,0 +1,33 @@
+package config
+
+const (
+	// FileName is the default configuration file name inside the configuration directory.
+	FileName = "config.yaml"
+
+	// EnvVarPrefix prefixes the environment variables setting flag values, see EnvVarName.
+	EnvVarPrefix = "KIMSUFI_NOTIFIER_"
+	// ProfileEnvVarName is the environment variable selecting the profile.
+	ProfileEnvVarName = EnvVarPrefix + "PROFILE"
+)
+
+// Config holds named profiles.
+type Config struct {
+	// DefaultProfile is used when no profile is selected.
+	DefaultProfile string             `yaml:"defaultProfile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile holds default flag values.
+type Profile struct {
+	Endpoint       string   `yaml:"endpoint,omitempty"`
+	Country        string   `yaml:"country,omitempty"`
+	NotifyWebhooks []string `yaml:"notifyWebhooks,omitempty"`
+
+	// Flags are the flag values of every command having the flag.
+	Flags Values `yaml:"flags,omitempty"`
+	// Commands are the flag values by command, e.g. "watch" or "orders list".
+	Commands map[string]Values `yaml:"commands,omitempty"`
+}
+
+// Values are flag values by flag name, given as a scalar, a list or a map of key=value.
+type Values map[string]any
@@ -0,0 +1,36 @@
+package credentials
+
+// Get returns the credentials saved for the endpoint, or nil if none were saved.
+func (f File) Get(endpoint string) *Credentials {
+	for _, c := range f.Credentials {
+		if c.Endpoint == endpoint {
+			return &c
+		}
+	}
+
+	return nil
+}
+
+// Set saves c, replacing the credentials saved for its endpoint.
+func (f *File) Set(c Credentials) {
+	for i := range f.Credentials {
+		if f.Credentials[i].Endpoint == c.Endpoint {
+			f.Credentials[i] = c
+			return
+		}
+	}
+
+	f.Credentials = append(f.Credentials, c)
+}
+
+// Remove removes the credentials saved for the endpoint, it returns false when none were saved.
+func (f *File) Remove(endpoint string) bool {
+	for i := range f.Credentials {
+		if f.Credentials[i].Endpoint == endpoint {
+			f.Credentials = append(f.Credentials[:i], f.Credentials[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package credentials
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// FileName is the default credentials file name inside the configuration directory.
+	FileName = "credentials.age"
+
+	// PassphraseEnvVarName is the environment variable holding the credentials file passphrase.
+	PassphraseEnvVarName = "KIMSUFI_NOTIFIER_CREDENTIALS_PASSPHRASE"
+)
+
+var (
+	// ErrMissingPassphrase is returned when the passphrase is empty.
+	ErrMissingPassphrase = errors.New("credentials file passphrase is required")
+)
+
+// PassphraseFunc returns the passphrase of the credentials file, create is true when the file does not exist yet.
+type PassphraseFunc func(create bool) (string, error)
+
+// File lists the saved credentials.
+type File struct {
+	Credentials []Credentials `json:"credentials"`
+}
+
+// Credentials are the OVH API credentials saved for an endpoint.
+type Credentials struct {
+	Endpoint    string    `json:"endpoint"`
+	AppKey      string    `json:"appKey"`
+	AppSecret   string    `json:"appSecret,omitempty"`
+	ConsumerKey string    `json:"consumerKey"`
+	Created     time.Time `json:"created"`
+}
@@ -0,0 +1,214 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/atomicfile"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Store persists the credentials in a file only readable by the user,
+// encrypted with age using a passphrase derived key.
+type Store struct {
+	path       string
+	passphrase PassphraseFunc
+	// key is the passphrase, asked at most once.
+	key string
+	// workFactor is the scrypt work factor, the age default when zero.
+	workFactor int
+}
+
+// NewStore creates a new Store backed by the file at path, encrypted with the passphrase returned by passphrase.
+// If path is empty, the default credentials file is used.
+func NewStore(path string, passphrase PassphraseFunc) (*Store, error) {
+	if path == "" {
+		p, err := xdg.ConfigFile(FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find credentials file: %w", err)
+		}
+		path = p
+	}
+
+	s := &Store{
+		path:       path,
+		passphrase: passphrase,
+	}
+
+	return s, nil
+}
+
+// Path returns the path of the credentials file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the saved credentials.
+// A missing credentials file is not an error and returns no credentials.
+func (s *Store) Load() (File, error) {
+	var f File
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+
+	data, err = s.decrypt(data)
+	if err != nil {
+		return f, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return f, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	return f, nil
+}
+
+// Get returns the credentials saved for the endpoint, or nil if none were saved.
+func (s *Store) Get(endpoint string) (*Credentials, error) {
+	f, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Get(endpoint), nil
+}
+
+// Set saves c in the credentials file, replacing the credentials saved for its endpoint.
+func (s *Store) Set(c Credentials) error {
+	f, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	f.Set(c)
+
+	return s.write(f)
+}
+
+// Remove removes the credentials saved for the endpoint, it returns false when none were saved.
+func (s *Store) Remove(endpoint string) (bool, error) {
+	f, err := s.Load()
+	if err != nil {
+		return false, err
+	}
+
+	if !f.Remove(endpoint) {
+		return false, nil
+	}
+
+	return true, s.write(f)
+}
+
+// getPassphrase returns the passphrase, asking it on first use.
+func (s *Store) getPassphrase() (string, error) {
+	if s.key != "" {
+		return s.key, nil
+	}
+
+	_, err := os.Stat(s.path)
+	create := errors.Is(err, fs.ErrNotExist)
+
+	if s.passphrase == nil {
+		return "", ErrMissingPassphrase
+	}
+
+	key, err := s.passphrase(create)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", ErrMissingPassphrase
+	}
+	s.key = key
+
+	return key, nil
+}
+
+// decrypt returns the decrypted credentials file content.
+func (s *Store) decrypt(data []byte) ([]byte, error) {
+	key, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := age.NewScryptIdentity(key)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(data), identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	return io.ReadAll(r)
+}
+
+// encrypt returns the encrypted credentials file content.
+func (s *Store) encrypt(data []byte) ([]byte, error) {
+	key, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := age.NewScryptRecipient(key)
+	if err != nil {
+		return nil, err
+	}
+	if s.workFactor > 0 {
+		recipient.SetWorkFactor(s.workFactor)
+	}
+
+	var b bytes.Buffer
+	w, err := age.Encrypt(&b, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// write replaces the credentials file content with f, encrypted.
+// The file is written next to the credentials file and renamed over it.
+func (s *Store) write(f File) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	data, err = s.encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(s.path, data, 0o600)
+}
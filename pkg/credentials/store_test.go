@@ -0,0 +1,96 @@
+package credentials
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", FileName)
+	passphrase := func(string) PassphraseFunc {
+		return func(bool) (string, error) { return "correct horse", nil }
+	}
+
+	s, err := NewStore(path, passphrase(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.workFactor = 10
+
+	c, err := s.Get("ovh-eu")
+	if err != nil {
+		t.Fatalf("Get() of a missing file failed: %v", err)
+	}
+	if c != nil {
+		t.Errorf("expected no credentials, got %v", c)
+	}
+
+	t0 := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	saved := []Credentials{
+		{Endpoint: "ovh-eu", AppKey: "app", AppSecret: "secret", ConsumerKey: "ck1", Created: t0},
+		{Endpoint: "ovh-ca", AppKey: "app", AppSecret: "secret", ConsumerKey: "ck2", Created: t0},
+		{Endpoint: "ovh-eu", AppKey: "app", AppSecret: "secret", ConsumerKey: "ck3", Created: t0.Add(time.Hour)},
+	}
+	for _, c := range saved {
+		err := s.Set(c)
+		if err != nil {
+			t.Fatalf("Set() failed: %v", err)
+		}
+	}
+
+	removed, err := s.Remove("ovh-ca")
+	if err != nil || !removed {
+		t.Fatalf("Remove() = %t, %v, want true", removed, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Errorf("credentials file is not encrypted")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credentials file mode = %o, want 600", perm)
+	}
+
+	reopened, err := NewStore(path, passphrase(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := reopened.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if diff := cmp.Diff([]Credentials{saved[2]}, f.Credentials); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+
+	wrong, err := NewStore(path, func(bool) (string, error) { return "wrong", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = wrong.Load()
+	if err == nil {
+		t.Errorf("Load() with a wrong passphrase succeeded, want error")
+	}
+
+	err = os.WriteFile(path, []byte(`{"credentials":[]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = reopened.Load()
+	if err == nil {
+		t.Errorf("Load() of a plain JSON file succeeded, want error")
+	}
+}
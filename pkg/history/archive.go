@@ -0,0 +1,175 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	ArchiveFormatCSV     = "csv"
+	ArchiveFormatJSONL   = "jsonl"
+	ArchiveFormatParquet = "parquet"
+)
+
+var (
+	// ArchiveFormats is the list of supported archive formats.
+	ArchiveFormats = []string{ArchiveFormatCSV, ArchiveFormatJSONL, ArchiveFormatParquet}
+
+	// csvHeaders is the header record of CSV archives.
+	csvHeaders = []string{"time", "endpoint", "planCode", "datacenter", "status"}
+)
+
+// archiveRecord is the row layout of Parquet archives.
+type archiveRecord struct {
+	Time       time.Time `parquet:"time,timestamp(millisecond)"`
+	Endpoint   string    `parquet:"endpoint,dict"`
+	PlanCode   string    `parquet:"planCode,dict"`
+	Datacenter string    `parquet:"datacenter,dict"`
+	Status     string    `parquet:"status,dict"`
+}
+
+// ArchiveFormatFromPath returns the archive format matching the file extension of path.
+func ArchiveFormatFromPath(path string) (string, error) {
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	if format == "json" || format == "ndjson" {
+		format = ArchiveFormatJSONL
+	}
+
+	if !slices.Contains(ArchiveFormats, format) {
+		return "", fmt.Errorf("unknown archive format for %q (allowed values: %s)", path, strings.Join(ArchiveFormats, ", "))
+	}
+
+	return format, nil
+}
+
+// Export writes the transitions to w in the given archive format.
+func Export(w io.Writer, format string, transitions Transitions) error {
+	switch format {
+	case ArchiveFormatCSV:
+		cw := csv.NewWriter(w)
+		err := cw.Write(csvHeaders)
+		if err != nil {
+			return err
+		}
+		for _, t := range transitions {
+			err := cw.Write([]string{t.Time.UTC().Format(time.RFC3339Nano), t.Endpoint, t.PlanCode, t.Datacenter, t.Status})
+			if err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case ArchiveFormatJSONL:
+		e := json.NewEncoder(w)
+		for _, t := range transitions {
+			err := e.Encode(t)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case ArchiveFormatParquet:
+		records := make([]archiveRecord, 0, len(transitions))
+		for _, t := range transitions {
+			records = append(records, archiveRecord(t))
+		}
+		return parquet.Write(w, records)
+	}
+
+	return fmt.Errorf("invalid archive format %q (allowed values: %s)", format, strings.Join(ArchiveFormats, ", "))
+}
+
+// Import reads transitions from r in the given archive format.
+func Import(r io.Reader, format string) (Transitions, error) {
+	var transitions Transitions
+
+	switch format {
+	case ArchiveFormatCSV:
+		cr := csv.NewReader(r)
+		records, err := cr.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		if !slices.Equal(records[0], csvHeaders) {
+			return nil, fmt.Errorf("invalid CSV header %v, expected %v", records[0], csvHeaders)
+		}
+		for i, record := range records[1:] {
+			t, err := time.Parse(time.RFC3339Nano, record[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+			transitions = append(transitions, Transition{
+				Time:       t,
+				Endpoint:   record[1],
+				PlanCode:   record[2],
+				Datacenter: record[3],
+				Status:     record[4],
+			})
+		}
+	case ArchiveFormatJSONL:
+		d := json.NewDecoder(r)
+		for d.More() {
+			var t Transition
+			err := d.Decode(&t)
+			if err != nil {
+				return nil, err
+			}
+			transitions = append(transitions, t)
+		}
+	case ArchiveFormatParquet:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		records, err := parquet.Read[archiveRecord](bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			transitions = append(transitions, Transition(record))
+		}
+	default:
+		return nil, fmt.Errorf("invalid archive format %q (allowed values: %s)", format, strings.Join(ArchiveFormats, ", "))
+	}
+
+	for i := range transitions {
+		transitions[i].Time = transitions[i].Time.UTC()
+	}
+
+	return transitions, nil
+}
+
+// Merge merges other transitions into the transitions.
+// Transitions are sorted by time, and transitions which do not change the
+// status of their plan and datacenter are dropped, this deduplicates
+// observations of the same changes made by several collectors.
+func (t Transitions) Merge(other Transitions) Transitions {
+	all := slices.Concat(t, other)
+	all.Sort()
+
+	var merged Transitions
+	latest := map[string]string{}
+	for _, transition := range all {
+		status, found := latest[transition.Key()]
+		if found && status == transition.Status {
+			continue
+		}
+
+		latest[transition.Key()] = transition.Status
+		merged = append(merged, transition)
+	}
+
+	return merged
+}
@@ -0,0 +1,69 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+)
+
+func TestExportImport(t *testing.T) {
+	transitions := Transitions{
+		transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(10, "rbx", kimsufiavailability.StatusAvailable),
+		transition(15, "gra", kimsufiavailability.StatusAvailable),
+	}
+
+	for _, format := range ArchiveFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Export(&buf, format, transitions)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			got, err := Import(&buf, format)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+
+			if diff := cmp.Diff(transitions, got); diff != "" {
+				t.Errorf("Import() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	collectorA := Transitions{
+		transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(10, "rbx", kimsufiavailability.StatusAvailable),
+		transition(20, "rbx", kimsufiavailability.StatusUnavailable),
+	}
+	collectorB := Transitions{
+		transition(5, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(11, "rbx", kimsufiavailability.StatusAvailable),
+		transition(21, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(21, "gra", kimsufiavailability.StatusUnavailable),
+	}
+
+	want := Transitions{
+		transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(10, "rbx", kimsufiavailability.StatusAvailable),
+		transition(20, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(21, "gra", kimsufiavailability.StatusUnavailable),
+	}
+
+	got := collectorA.Merge(collectorB)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Merge() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Merging twice is idempotent
+	got = got.Merge(collectorA)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Merge() is not idempotent (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,242 @@
+package history
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+)
+
+// NewObservationsFromAvailabilities converts server availabilities into observations.
+// A plan is considered available in a datacenter when any of its memory and storage
+// combinations is available there.
+func NewObservationsFromAvailabilities(availabilities kimsufiavailability.Availabilities) Observations {
+	status := map[[2]string]bool{}
+	var keys [][2]string
+
+	for _, a := range availabilities {
+		for _, dc := range a.Datacenters {
+			key := [2]string{a.PlanCode, dc.Datacenter}
+			available, found := status[key]
+			if !found {
+				keys = append(keys, key)
+			}
+			status[key] = available || dc.IsAvailable()
+		}
+	}
+
+	var observations Observations
+	for _, key := range keys {
+		o := Observation{
+			PlanCode:   key[0],
+			Datacenter: key[1],
+			Status:     kimsufiavailability.StatusUnavailable,
+		}
+		if status[key] {
+			o.Status = kimsufiavailability.StatusAvailable
+		}
+		observations = append(observations, o)
+	}
+
+	return observations
+}
+
+// NewObservationsFromVPSAvailabilities converts VPS availabilities of planCode into observations.
+func NewObservationsFromVPSAvailabilities(planCode string, availabilities kimsufiavailability.VPSAvailabilities) Observations {
+	var observations Observations
+
+	for _, dc := range availabilities.Datacenters {
+		o := Observation{
+			PlanCode:   planCode,
+			Datacenter: dc.Datacenter,
+			Status:     kimsufiavailability.StatusUnavailable,
+		}
+		if availabilities.IsDatacenterAvailable(dc.Datacenter) {
+			o.Status = kimsufiavailability.StatusAvailable
+		}
+		observations = append(observations, o)
+	}
+
+	return observations
+}
+
+// Key returns the identifier of the plan and datacenter the transition applies to.
+func (t Transition) Key() string {
+	return strings.Join([]string{t.Endpoint, t.PlanCode, t.Datacenter}, "/")
+}
+
+// IsAvailable returns true if the transition marks the start of an availability window.
+func (t Transition) IsAvailable() bool {
+	return t.Status == kimsufiavailability.StatusAvailable
+}
+
+// Match returns true if the transition matches the filter.
+func (f Filter) Match(t Transition) bool {
+	if f.Endpoint != "" && f.Endpoint != t.Endpoint {
+		return false
+	}
+
+	if f.PlanCode != "" && f.PlanCode != t.PlanCode {
+		return false
+	}
+
+	if len(f.Datacenters) > 0 && !slices.ContainsFunc(f.Datacenters, func(dc string) bool {
+		return strings.EqualFold(dc, t.Datacenter)
+	}) {
+		return false
+	}
+
+	if !f.Since.IsZero() && t.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !t.Time.Before(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the transitions matching f.
+func (t Transitions) Filter(f Filter) Transitions {
+	var result Transitions
+
+	for _, transition := range t {
+		if f.Match(transition) {
+			result = append(result, transition)
+		}
+	}
+
+	return result
+}
+
+// Sort sorts transitions by time, keeping the insertion order of equal times.
+func (t Transitions) Sort() {
+	sort.SliceStable(t, func(i, j int) bool {
+		return t[i].Time.Before(t[j].Time)
+	})
+}
+
+// Latest returns the most recent transition for each plan and datacenter.
+func (t Transitions) Latest() map[string]Transition {
+	latest := map[string]Transition{}
+
+	for _, transition := range t {
+		current, found := latest[transition.Key()]
+		if !found || !transition.Time.Before(current.Time) {
+			latest[transition.Key()] = transition
+		}
+	}
+
+	return latest
+}
+
+// Span returns the time of the first and the last transition.
+func (t Transitions) Span() (time.Time, time.Time) {
+	var first, last time.Time
+
+	for _, transition := range t {
+		if first.IsZero() || transition.Time.Before(first) {
+			first = transition.Time
+		}
+		if last.IsZero() || transition.Time.After(last) {
+			last = transition.Time
+		}
+	}
+
+	return first, last
+}
+
+// Windows returns the availability windows found in the transitions.
+// A window opens on an available transition and closes on the next
+// unavailable transition of the same plan and datacenter.
+// Windows are sorted by start time.
+func (t Transitions) Windows() Windows {
+	sorted := slices.Clone(t)
+	sorted.Sort()
+
+	var windows Windows
+	open := map[string]int{}
+
+	for _, transition := range sorted {
+		index, isOpen := open[transition.Key()]
+
+		if transition.IsAvailable() {
+			if isOpen {
+				continue
+			}
+
+			windows = append(windows, Window{
+				Endpoint:   transition.Endpoint,
+				PlanCode:   transition.PlanCode,
+				Datacenter: transition.Datacenter,
+				Start:      transition.Time,
+			})
+			open[transition.Key()] = len(windows) - 1
+			continue
+		}
+
+		if isOpen {
+			windows[index].End = transition.Time
+			delete(open, transition.Key())
+		}
+	}
+
+	return windows
+}
+
+// IsOpen returns true if the window has not been closed yet.
+func (w Window) IsOpen() bool {
+	return w.End.IsZero()
+}
+
+// Duration returns the window duration, open windows are measured up to now.
+func (w Window) Duration(now time.Time) time.Duration {
+	if w.IsOpen() {
+		return now.Sub(w.Start)
+	}
+
+	return w.End.Sub(w.Start)
+}
+
+// Closed returns the windows which have been closed.
+func (w Windows) Closed() Windows {
+	var result Windows
+
+	for _, window := range w {
+		if !window.IsOpen() {
+			result = append(result, window)
+		}
+	}
+
+	return result
+}
+
+// Datacenters returns the sorted list of datacenters found in the windows.
+func (w Windows) Datacenters() []string {
+	var datacenters []string
+
+	for _, window := range w {
+		if !slices.Contains(datacenters, window.Datacenter) {
+			datacenters = append(datacenters, window.Datacenter)
+		}
+	}
+	slices.Sort(datacenters)
+
+	return datacenters
+}
+
+// ByDatacenter returns the windows of the given datacenter.
+func (w Windows) ByDatacenter(datacenter string) Windows {
+	var result Windows
+
+	for _, window := range w {
+		if window.Datacenter == datacenter {
+			result = append(result, window)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,193 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+)
+
+var (
+	t0 = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+)
+
+func transition(minutes int, datacenter, status string) Transition {
+	return Transition{
+		Time:       t0.Add(time.Duration(minutes) * time.Minute),
+		Endpoint:   "ovh-eu",
+		PlanCode:   "24ska01",
+		Datacenter: datacenter,
+		Status:     status,
+	}
+}
+
+func TestWindows(t *testing.T) {
+	testCases := []struct {
+		name        string
+		transitions Transitions
+		want        Windows
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name: "never available",
+			transitions: Transitions{
+				transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+			},
+			want: nil,
+		},
+		{
+			name: "closed window",
+			transitions: Transitions{
+				transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+				transition(10, "rbx", kimsufiavailability.StatusAvailable),
+				transition(15, "rbx", kimsufiavailability.StatusUnavailable),
+			},
+			want: Windows{
+				{Endpoint: "ovh-eu", PlanCode: "24ska01", Datacenter: "rbx", Start: t0.Add(10 * time.Minute), End: t0.Add(15 * time.Minute)},
+			},
+		},
+		{
+			name: "open window",
+			transitions: Transitions{
+				transition(10, "rbx", kimsufiavailability.StatusAvailable),
+			},
+			want: Windows{
+				{Endpoint: "ovh-eu", PlanCode: "24ska01", Datacenter: "rbx", Start: t0.Add(10 * time.Minute)},
+			},
+		},
+		{
+			name: "interleaved datacenters out of order",
+			transitions: Transitions{
+				transition(20, "gra", kimsufiavailability.StatusUnavailable),
+				transition(5, "gra", kimsufiavailability.StatusAvailable),
+				transition(10, "rbx", kimsufiavailability.StatusAvailable),
+				transition(30, "rbx", kimsufiavailability.StatusUnavailable),
+			},
+			want: Windows{
+				{Endpoint: "ovh-eu", PlanCode: "24ska01", Datacenter: "gra", Start: t0.Add(5 * time.Minute), End: t0.Add(20 * time.Minute)},
+				{Endpoint: "ovh-eu", PlanCode: "24ska01", Datacenter: "rbx", Start: t0.Add(10 * time.Minute), End: t0.Add(30 * time.Minute)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.transitions.Windows()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Windows() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewObservationsFromAvailabilities(t *testing.T) {
+	availabilities := kimsufiavailability.Availabilities{
+		{
+			PlanCode: "24ska01",
+			Datacenters: []kimsufiavailability.Datacenter{
+				{Datacenter: "rbx", Availability: kimsufiavailability.StatusUnavailable},
+				{Datacenter: "gra", Availability: kimsufiavailability.StatusUnavailable},
+			},
+		},
+		{
+			PlanCode: "24ska01",
+			Datacenters: []kimsufiavailability.Datacenter{
+				{Datacenter: "rbx", Availability: "1H-low"},
+				{Datacenter: "gra", Availability: kimsufiavailability.StatusUnavailable},
+			},
+		},
+	}
+
+	want := Observations{
+		{PlanCode: "24ska01", Datacenter: "rbx", Status: kimsufiavailability.StatusAvailable},
+		{PlanCode: "24ska01", Datacenter: "gra", Status: kimsufiavailability.StatusUnavailable},
+	}
+
+	got := NewObservationsFromAvailabilities(availabilities)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewObservationsFromAvailabilities() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreRecord(t *testing.T) {
+	s, err := NewStore(t.TempDir() + "/history.jsonl")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	steps := []struct {
+		observations Observations
+		want         int
+	}{
+		{
+			observations: Observations{{PlanCode: "24ska01", Datacenter: "rbx", Status: kimsufiavailability.StatusUnavailable}},
+			want:         1,
+		},
+		{
+			observations: Observations{{PlanCode: "24ska01", Datacenter: "rbx", Status: kimsufiavailability.StatusUnavailable}},
+			want:         0,
+		},
+		{
+			observations: Observations{{PlanCode: "24ska01", Datacenter: "rbx", Status: kimsufiavailability.StatusAvailable}},
+			want:         1,
+		},
+	}
+
+	for i, step := range steps {
+		changes, err := s.Record(t0.Add(time.Duration(i)*time.Minute), "ovh-eu", step.observations)
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if len(changes) != step.want {
+			t.Errorf("step %d: expected %d changes, got %d", i, step.want, len(changes))
+		}
+	}
+
+	transitions, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(transitions) != 2 {
+		t.Errorf("expected 2 transitions, got %d", len(transitions))
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value         string
+		expected      time.Time
+		expectedError bool
+	}{
+		{value: "", expected: time.Time{}},
+		{value: "2024-01-01", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-01-01T10:00:00Z", expected: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "36h", expected: now.Add(-36 * time.Hour)},
+		{value: "7d", expected: now.Add(-7 * 24 * time.Hour)},
+		{value: "yesterday", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			actual, err := ParseTime(tc.value, now)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("expected error for %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTime failed: %v", err)
+			}
+			if !actual.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package history
+
+import "time"
+
+const (
+	// FileName is the default history file name inside the data directory.
+	FileName = "history.jsonl"
+
+	EventTypeAvailable   = "availability.available"
+	EventTypeUnavailable = "availability.unavailable"
+)
+
+type Transitions []Transition
+
+// Transition records a change of availability status for a plan in a datacenter.
+// The first observation of a plan and datacenter is always recorded, so that
+// the history knows since when it has been watching.
+type Transition struct {
+	Time       time.Time `json:"time"`
+	Endpoint   string    `json:"endpoint"`
+	PlanCode   string    `json:"planCode"`
+	Datacenter string    `json:"datacenter"`
+	Status     string    `json:"status"`
+}
+
+type Observations []Observation
+
+// Observation is the availability status of a plan in a datacenter at a point in time.
+type Observation struct {
+	PlanCode   string
+	Datacenter string
+	Status     string
+}
+
+type Windows []Window
+
+// Window is a period during which a plan was available in a datacenter.
+// End is zero while the window is still open.
+type Window struct {
+	Endpoint   string    `json:"endpoint"`
+	PlanCode   string    `json:"planCode"`
+	Datacenter string    `json:"datacenter"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end,omitempty"`
+}
+
+// Filter selects transitions, empty fields match everything.
+type Filter struct {
+	Endpoint    string
+	PlanCode    string
+	Datacenters []string
+	Since       time.Time
+	Until       time.Time
+}
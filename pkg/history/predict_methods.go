@@ -0,0 +1,197 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// seasonalPriorHours is the pseudo observation time given to the global rate
+	// in every hour and day bucket, it smooths buckets which were rarely observed.
+	seasonalPriorHours = 1.0
+
+	// minInterArrivalSamples is the minimum number of times between restocks
+	// needed to use the inter-arrival component.
+	minInterArrivalSamples = 2
+)
+
+// Predict estimates, per datacenter and for all datacenters combined,
+// the probability of a restock within horizon after now.
+// Hours of the week are computed in loc.
+func Predict(transitions Transitions, now time.Time, horizon time.Duration, loc *time.Location) Predictions {
+	windows := transitions.Windows()
+
+	var predictions Predictions
+	predictions = append(predictions, predict(DatacenterAny, transitions, windows, now, horizon, loc))
+
+	for _, dc := range windows.Datacenters() {
+		dcTransitions := transitions.Filter(Filter{Datacenters: []string{dc}})
+		predictions = append(predictions, predict(dc, dcTransitions, windows.ByDatacenter(dc), now, horizon, loc))
+	}
+
+	return predictions
+}
+
+// predict computes a single prediction from the transitions and their windows.
+func predict(datacenter string, transitions Transitions, windows Windows, now time.Time, horizon time.Duration, loc *time.Location) Prediction {
+	from, _ := transitions.Span()
+
+	p := Prediction{
+		Datacenter: datacenter,
+		Horizon:    horizon,
+		Windows:    len(windows),
+		Observed:   now.Sub(from),
+	}
+
+	if len(windows) == 0 || p.Observed <= 0 {
+		p.Explanation = fmt.Sprintf("no restock observed in %s", formatDuration(p.Observed))
+		return p
+	}
+
+	starts := make([]time.Time, 0, len(windows))
+	for _, w := range windows {
+		starts = append(starts, w.Start)
+		if w.IsOpen() {
+			p.Available = true
+		}
+	}
+	slices.SortFunc(starts, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+	p.LastRestock = starts[len(starts)-1]
+
+	// Seasonal component
+	p.ExpectedRestocks = seasonalExpectation(starts, from, now, horizon, loc)
+	p.Seasonal = 1 - math.Exp(-p.ExpectedRestocks)
+
+	// Inter-arrival component
+	elapsed := now.Sub(p.LastRestock)
+	interArrival, samples := interArrivalProbability(starts, elapsed, horizon)
+	p.InterArrival = interArrival
+	p.InterArrivalSamples = samples
+
+	// Combine components
+	switch {
+	case p.Available:
+		p.Probability = 1
+	case p.InterArrival != nil:
+		p.Probability = (p.Seasonal + *p.InterArrival) / 2
+	default:
+		p.Probability = p.Seasonal
+	}
+
+	p.Explanation = p.explain(elapsed)
+
+	return p
+}
+
+// seasonalExpectation returns the expected number of restocks within horizon.
+// The restock rate at a given time is the rate of its hour of the day,
+// scaled by how its day of the week compares to the global rate.
+// Each rate is the number of restocks started in the bucket divided by the
+// time the bucket was observed, smoothed towards the global rate.
+func seasonalExpectation(starts []time.Time, from, now time.Time, horizon time.Duration, loc *time.Location) float64 {
+	var hourCounts, hourExposure [24]float64
+	var dayCounts, dayExposure [7]float64
+
+	for _, s := range starts {
+		s = s.In(loc)
+		hourCounts[s.Hour()]++
+		dayCounts[s.Weekday()]++
+	}
+
+	eachHour(from, now, loc, func(t time.Time, hours float64) {
+		hourExposure[t.Hour()] += hours
+		dayExposure[t.Weekday()] += hours
+	})
+
+	globalRate := float64(len(starts)) / now.Sub(from).Hours()
+	smoothedRate := func(count, exposure float64) float64 {
+		return (count + seasonalPriorHours*globalRate) / (exposure + seasonalPriorHours)
+	}
+
+	var expected float64
+	eachHour(now, now.Add(horizon), loc, func(t time.Time, hours float64) {
+		hourRate := smoothedRate(hourCounts[t.Hour()], hourExposure[t.Hour()])
+		dayFactor := smoothedRate(dayCounts[t.Weekday()], dayExposure[t.Weekday()]) / globalRate
+		expected += hourRate * dayFactor * hours
+	})
+
+	return expected
+}
+
+// eachHour calls fn for every hour of the clock between from and to in loc,
+// with the number of hours of that clock hour which are within the range.
+func eachHour(from, to time.Time, loc *time.Location, fn func(t time.Time, hours float64)) {
+	for t := from.In(loc); t.Before(to); {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if next.After(to) {
+			next = to
+		}
+
+		fn(t, next.Sub(t).Hours())
+		t = next
+	}
+}
+
+// interArrivalProbability returns the probability that the next restock starts
+// within horizon, knowing that elapsed time passed since the last one.
+// It is the share of past times between restocks longer than elapsed which
+// ended within elapsed+horizon. It returns nil when there is not enough data.
+func interArrivalProbability(starts []time.Time, elapsed, horizon time.Duration) (*float64, int) {
+	var longer, within int
+
+	for i := 1; i < len(starts); i++ {
+		gap := starts[i].Sub(starts[i-1])
+		if gap <= elapsed {
+			continue
+		}
+
+		longer++
+		if gap <= elapsed+horizon {
+			within++
+		}
+	}
+
+	if longer < minInterArrivalSamples {
+		return nil, longer
+	}
+
+	p := float64(within) / float64(longer)
+	return &p, longer
+}
+
+// explain returns a human readable explanation of the prediction.
+func (p Prediction) explain(elapsed time.Duration) string {
+	explanation := fmt.Sprintf("%d restocks in %s, %.2f expected in the next %s from hour and weekday rates",
+		p.Windows, formatDuration(p.Observed), p.ExpectedRestocks, formatDuration(p.Horizon))
+
+	if p.Available {
+		return "available now; " + explanation
+	}
+
+	if p.InterArrival != nil {
+		explanation += fmt.Sprintf("; last restock %s ago, %.0f%% of %d longer gaps ended within the horizon",
+			formatDuration(elapsed), *p.InterArrival*100, p.InterArrivalSamples)
+	} else {
+		explanation += fmt.Sprintf("; last restock %s ago, too few longer gaps to compare", formatDuration(elapsed))
+	}
+
+	return explanation
+}
+
+// PollInterval returns a polling interval for the given restock probability,
+// scaled linearly between max for a probability of 0 and min for a probability of 1.
+// It lets a watcher poll more aggressively during high probability windows.
+func PollInterval(probability float64, min, max time.Duration) time.Duration {
+	probability = math.Max(0, math.Min(1, probability))
+
+	return max - time.Duration(probability*float64(max-min))
+}
+
+// formatDuration formats a duration rounded to the minute.
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Minute).String()
+}
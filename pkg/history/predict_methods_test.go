@@ -0,0 +1,95 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+)
+
+// dailyRestocks returns transitions of a 10 minutes restock every day at 9:00 for the given number of days.
+func dailyRestocks(days int) Transitions {
+	transitions := Transitions{
+		transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+	}
+
+	for day := range days {
+		start := day*24*60 + 60
+		transitions = append(transitions,
+			transition(start, "rbx", kimsufiavailability.StatusAvailable),
+			transition(start+10, "rbx", kimsufiavailability.StatusUnavailable),
+		)
+	}
+
+	return transitions
+}
+
+func TestPredict(t *testing.T) {
+	transitions := dailyRestocks(14)
+
+	testCases := []struct {
+		name    string
+		now     time.Time
+		horizon time.Duration
+		min     float64
+		max     float64
+	}{
+		{
+			name:    "before the usual restock time",
+			now:     t0.Add(14 * 24 * time.Hour),
+			horizon: 2 * time.Hour,
+			min:     0.7,
+			max:     1,
+		},
+		{
+			name:    "away from the usual restock time",
+			now:     t0.Add(14*24*time.Hour + 8*time.Hour),
+			horizon: time.Hour,
+			min:     0,
+			max:     0.1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			predictions := Predict(transitions, tc.now, tc.horizon, time.UTC)
+			if len(predictions) != 2 {
+				t.Fatalf("expected 2 predictions, got %d", len(predictions))
+			}
+
+			p := predictions[1]
+			if p.Datacenter != "rbx" {
+				t.Errorf("expected rbx datacenter, got %s", p.Datacenter)
+			}
+			if p.Probability < tc.min || p.Probability > tc.max {
+				t.Errorf("expected probability in [%.2f, %.2f], got %.2f (%s)", tc.min, tc.max, p.Probability, p.Explanation)
+			}
+		})
+	}
+}
+
+func TestPredictNoHistory(t *testing.T) {
+	predictions := Predict(nil, t0, time.Hour, time.UTC)
+	if len(predictions) != 1 || predictions[0].Probability != 0 {
+		t.Errorf("expected a single zero prediction, got %+v", predictions)
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	testCases := []struct {
+		probability float64
+		expected    time.Duration
+	}{
+		{probability: 0, expected: 10 * time.Minute},
+		{probability: 0.5, expected: 5*time.Minute + 30*time.Second},
+		{probability: 1, expected: time.Minute},
+		{probability: 2, expected: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		actual := PollInterval(tc.probability, time.Minute, 10*time.Minute)
+		if actual != tc.expected {
+			t.Errorf("probability %.1f: expected %s, got %s", tc.probability, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package history
+
+import "time"
+
+const (
+	// DatacenterAny is the datacenter name used for predictions over all datacenters.
+	DatacenterAny = "any"
+)
+
+type Predictions []Prediction
+
+// Prediction is the estimated probability of a restock within a horizon.
+// The probability combines two components which are kept for explanation:
+// seasonal rates by hour of the day and day of the week, and the
+// empirical distribution of times between restocks.
+type Prediction struct {
+	Datacenter string        `json:"datacenter"`
+	Horizon    time.Duration `json:"horizon"`
+
+	// Probability is the combined probability of at least one restock within the horizon.
+	Probability float64 `json:"probability"`
+	// Available is true when a window is currently open.
+	Available bool `json:"available"`
+
+	// Seasonal is the probability derived from the restock rates by hour of the day and day of the week.
+	Seasonal float64 `json:"seasonal"`
+	// ExpectedRestocks is the expected number of restocks within the horizon from the seasonal rate.
+	ExpectedRestocks float64 `json:"expectedRestocks"`
+
+	// InterArrival is the probability derived from the times between restocks,
+	// given the time elapsed since the last one. It is nil when there is not enough data.
+	InterArrival *float64 `json:"interArrival,omitempty"`
+	// InterArrivalSamples is the number of times between restocks longer than the elapsed time.
+	InterArrivalSamples int `json:"interArrivalSamples"`
+
+	Windows     int           `json:"windows"`
+	Observed    time.Duration `json:"observed"`
+	LastRestock time.Time     `json:"lastRestock,omitempty"`
+	Explanation string        `json:"explanation"`
+}
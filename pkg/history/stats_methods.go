@@ -0,0 +1,111 @@
+package history
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// NewStats computes statistics over the transitions, observed up to now.
+// Hours and days of the week are computed in loc.
+func NewStats(transitions Transitions, now time.Time, loc *time.Location) Stats {
+	from, _ := transitions.Span()
+	windows := transitions.Windows()
+	days := now.Sub(from).Hours() / 24
+
+	s := Stats{
+		From:           from,
+		To:             now,
+		Windows:        len(windows),
+		RestocksPerDay: perDay(len(windows), days),
+		MeanInterval:   meanInterval(windows),
+		Durations:      NewDurationStats(windows.Closed(), now),
+	}
+
+	for _, w := range windows {
+		start := w.Start.In(loc)
+		s.HourOfDay[start.Hour()]++
+		s.DayOfWeek[start.Weekday()]++
+		s.Heatmap[start.Weekday()][start.Hour()]++
+	}
+
+	for _, dc := range windows.Datacenters() {
+		dcWindows := windows.ByDatacenter(dc)
+		d := DatacenterStats{
+			Datacenter:     dc,
+			Windows:        len(dcWindows),
+			RestocksPerDay: perDay(len(dcWindows), days),
+			LastRestock:    dcWindows[len(dcWindows)-1].Start,
+			Durations:      NewDurationStats(dcWindows.Closed(), now),
+		}
+		s.Datacenters = append(s.Datacenters, d)
+	}
+
+	return s
+}
+
+// NewDurationStats computes the distribution of the window durations.
+func NewDurationStats(windows Windows, now time.Time) DurationStats {
+	if len(windows) == 0 {
+		return DurationStats{}
+	}
+
+	var durations []time.Duration
+	var total time.Duration
+	for _, w := range windows {
+		d := w.Duration(now)
+		durations = append(durations, d)
+		total += d
+	}
+	slices.Sort(durations)
+
+	return DurationStats{
+		Count:  len(durations),
+		Min:    durations[0],
+		Mean:   total / time.Duration(len(durations)),
+		Median: Percentile(durations, 50),
+		P75:    Percentile(durations, 75),
+		P90:    Percentile(durations, 90),
+		P95:    Percentile(durations, 95),
+		Max:    durations[len(durations)-1],
+	}
+}
+
+// Percentile returns the p-th percentile of sorted durations,
+// linearly interpolated between the closest ranks.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	fraction := rank - float64(lower)
+	return sorted[lower] + time.Duration(fraction*float64(sorted[upper]-sorted[lower]))
+}
+
+// meanInterval returns the mean time between consecutive window starts.
+func meanInterval(windows Windows) time.Duration {
+	if len(windows) < 2 {
+		return 0
+	}
+
+	first := windows[0].Start
+	last := windows[len(windows)-1].Start
+
+	return last.Sub(first) / time.Duration(len(windows)-1)
+}
+
+// perDay returns count divided by days, or 0 when days is not positive.
+func perDay(count int, days float64) float64 {
+	if days <= 0 {
+		return 0
+	}
+
+	return float64(count) / days
+}
@@ -0,0 +1,68 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{1 * time.Minute, 2 * time.Minute, 3 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+
+	testCases := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{p: 0, expected: 1 * time.Minute},
+		{p: 50, expected: 3 * time.Minute},
+		{p: 90, expected: 4*time.Minute + 36*time.Second},
+		{p: 100, expected: 5 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		actual := Percentile(durations, tc.p)
+		if actual != tc.expected {
+			t.Errorf("p%.0f: expected %s, got %s", tc.p, tc.expected, actual)
+		}
+	}
+
+	if Percentile(nil, 50) != 0 {
+		t.Errorf("expected 0 for empty durations")
+	}
+}
+
+func TestNewStats(t *testing.T) {
+	transitions := Transitions{
+		transition(0, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(60, "rbx", kimsufiavailability.StatusAvailable),
+		transition(70, "rbx", kimsufiavailability.StatusUnavailable),
+		transition(24*60+60, "gra", kimsufiavailability.StatusAvailable),
+		transition(24*60+90, "gra", kimsufiavailability.StatusUnavailable),
+	}
+	now := t0.Add(48 * time.Hour)
+
+	s := NewStats(transitions, now, time.UTC)
+
+	if s.Windows != 2 {
+		t.Errorf("expected 2 windows, got %d", s.Windows)
+	}
+	if s.RestocksPerDay != 1 {
+		t.Errorf("expected 1 restock per day, got %f", s.RestocksPerDay)
+	}
+	if s.MeanInterval != 24*time.Hour {
+		t.Errorf("expected 24h mean interval, got %s", s.MeanInterval)
+	}
+	if s.Durations.Median != 20*time.Minute {
+		t.Errorf("expected 20m median duration, got %s", s.Durations.Median)
+	}
+	if s.HourOfDay[9] != 2 {
+		t.Errorf("expected 2 restocks at 9h, got %d", s.HourOfDay[9])
+	}
+	if s.Heatmap[time.Monday][9] != 1 || s.Heatmap[time.Tuesday][9] != 1 {
+		t.Errorf("unexpected heatmap: %v", s.Heatmap)
+	}
+	if len(s.Datacenters) != 2 || s.Datacenters[0].Datacenter != "gra" {
+		t.Errorf("unexpected datacenters: %+v", s.Datacenters)
+	}
+}
@@ -0,0 +1,43 @@
+package history
+
+import "time"
+
+// Stats summarises the availability windows of a history.
+type Stats struct {
+	From           time.Time     `json:"from"`
+	To             time.Time     `json:"to"`
+	Windows        int           `json:"windows"`
+	RestocksPerDay float64       `json:"restocksPerDay"`
+	MeanInterval   time.Duration `json:"meanInterval"`
+	Durations      DurationStats `json:"durations"`
+
+	// HourOfDay counts window starts per hour of the day (0-23).
+	HourOfDay [24]int `json:"hourOfDay"`
+	// DayOfWeek counts window starts per day of the week, starting on Sunday.
+	DayOfWeek [7]int `json:"dayOfWeek"`
+	// Heatmap counts window starts per day of the week and hour of the day.
+	Heatmap [7][24]int `json:"heatmap"`
+
+	Datacenters []DatacenterStats `json:"datacenters"`
+}
+
+// DurationStats describes the distribution of closed window durations.
+type DurationStats struct {
+	Count  int           `json:"count"`
+	Min    time.Duration `json:"min"`
+	Mean   time.Duration `json:"mean"`
+	Median time.Duration `json:"median"`
+	P75    time.Duration `json:"p75"`
+	P90    time.Duration `json:"p90"`
+	P95    time.Duration `json:"p95"`
+	Max    time.Duration `json:"max"`
+}
+
+// DatacenterStats summarises the availability windows of a single datacenter.
+type DatacenterStats struct {
+	Datacenter     string        `json:"datacenter"`
+	Windows        int           `json:"windows"`
+	RestocksPerDay float64       `json:"restocksPerDay"`
+	LastRestock    time.Time     `json:"lastRestock"`
+	Durations      DurationStats `json:"durations"`
+}
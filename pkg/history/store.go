@@ -0,0 +1,113 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/jsonl"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Store persists availability transitions in a JSON lines file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a new Store backed by the file at path.
+// If path is empty, the default history file is used.
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		p, err := xdg.DataFile(FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find history file: %w", err)
+		}
+		path = p
+	}
+
+	s := &Store{
+		path: path,
+	}
+
+	return s, nil
+}
+
+// Path returns the path of the history file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns all the recorded transitions, sorted by time.
+// A missing history file is not an error and returns no transitions.
+func (s *Store) Load() (Transitions, error) {
+	values, err := jsonl.Read[Transition](s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := Transitions(values)
+	transitions.Sort()
+
+	return transitions, nil
+}
+
+// Append writes the transitions at the end of the history file.
+func (s *Store) Append(transitions ...Transition) error {
+	return jsonl.Append(s.path, transitions...)
+}
+
+// Record compares the observations with the last known status
+// of each plan and datacenter and appends the ones which changed.
+// It returns the recorded transitions.
+func (s *Store) Record(now time.Time, endpoint string, observations Observations) (Transitions, error) {
+	transitions, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := transitions.Latest()
+
+	var changes Transitions
+	for _, o := range observations {
+		t := Transition{
+			Time:       now.UTC(),
+			Endpoint:   endpoint,
+			PlanCode:   o.PlanCode,
+			Datacenter: o.Datacenter,
+			Status:     o.Status,
+		}
+
+		previous, found := latest[t.Key()]
+		if found && previous.Status == t.Status {
+			continue
+		}
+
+		latest[t.Key()] = t
+		changes = append(changes, t)
+	}
+
+	err = s.Append(changes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Import merges the transitions into the history file, see Transitions.Merge.
+// The history file is replaced at once, it is never left partially written.
+// It returns the number of transitions in the history before and after the import.
+func (s *Store) Import(transitions Transitions) (int, int, error) {
+	existing, err := s.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	merged := existing.Merge(transitions)
+
+	err = jsonl.Write(s.path, merged)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(existing), len(merged), nil
+}
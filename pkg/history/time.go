@@ -0,0 +1,48 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTime parses a point in time given either as an absolute
+// RFC 3339 timestamp or date (e.g. 2024-01-31), or as a duration
+// relative to now (e.g. 36h, 7d).
+// An empty value returns the zero time.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		t, err := time.ParseInLocation(layout, value, now.Location())
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	d, err := ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected a date, a RFC 3339 timestamp or a duration", value)
+	}
+
+	return now.Add(-d), nil
+}
+
+// ParseDuration parses a duration like time.ParseDuration,
+// with additional support for a day unit (e.g. 7d).
+func ParseDuration(value string) (time.Duration, error) {
+	days, ok := strings.CutSuffix(value, "d")
+	if ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
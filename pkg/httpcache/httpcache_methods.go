@@ -0,0 +1,24 @@
+package httpcache
+
+import (
+	"strings"
+	"time"
+)
+
+// TTL returns how long the responses of path are kept,
+// following the rule with the longest matching prefix, 0 when none matches.
+func (r Rules) TTL(path string) time.Duration {
+	var (
+		ttl     time.Duration
+		longest = -1
+	)
+
+	for _, rule := range r {
+		if strings.HasPrefix(path, rule.Prefix) && len(rule.Prefix) > longest {
+			ttl = rule.TTL
+			longest = len(rule.Prefix)
+		}
+	}
+
+	return ttl
+}
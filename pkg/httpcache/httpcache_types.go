@@ -0,0 +1,35 @@
+package httpcache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// DirName is the default responses directory name inside the cache directory.
+	DirName = "http"
+)
+
+type Rules []Rule
+
+// Rule sets how long the responses of the API paths starting with Prefix are kept.
+// A zero TTL disables caching for those paths.
+type Rule struct {
+	Prefix string
+	TTL    time.Duration
+}
+
+// DefaultRules caches the public catalogs, which rarely change and are large,
+// while availabilities are always fetched so that restocks are never missed.
+var DefaultRules = Rules{
+	{Prefix: "/order/catalog/public/", TTL: 12 * time.Hour},
+	{Prefix: "/dedicated/server/datacenter/availabilities", TTL: 0},
+	{Prefix: "/vps/order/rule/datacenter", TTL: 0},
+}
+
+// entry is the file content of a cached response.
+type entry struct {
+	Time     time.Time       `json:"time"`
+	Key      string          `json:"key"`
+	Response json.RawMessage `json:"response"`
+}
@@ -0,0 +1,160 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/atomicfile"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Cache persists API responses on disk, one file per request.
+// Responses are kept for the TTL of their path, see Rules.
+type Cache struct {
+	dir   string
+	rules Rules
+	// refresh ignores the cached responses, new responses are still stored.
+	refresh bool
+	now     func() time.Time
+}
+
+// NewCache creates a new Cache in dir following rules.
+// If dir is empty, the default responses directory is used.
+func NewCache(dir string, rules Rules) (*Cache, error) {
+	if dir == "" {
+		d, err := xdg.CacheFile(DirName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find cache directory: %w", err)
+		}
+		dir = d
+	}
+
+	c := &Cache{
+		dir:   dir,
+		rules: rules,
+		now:   time.Now,
+	}
+
+	return c, nil
+}
+
+// Dir returns the responses directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// WithRefresh returns a copy of the Cache which ignores the cached responses
+// and replaces them with the new ones.
+func (c *Cache) WithRefresh() *Cache {
+	newCache := *c
+	newCache.refresh = true
+
+	return &newCache
+}
+
+// Cacheable returns true if the responses of path are kept.
+func (c *Cache) Cacheable(path string) bool {
+	return c.rules.TTL(path) > 0
+}
+
+// Get reads the cached response of key into response.
+// path is the API path of the request, it selects the TTL.
+// It returns false when no response is cached or when it expired.
+func (c *Cache) Get(key, path string, response any) (bool, error) {
+	ttl := c.rules.TTL(path)
+	if ttl <= 0 || c.refresh {
+		return false, nil
+	}
+
+	file := c.path(key)
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var e entry
+	err = json.Unmarshal(data, &e)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if e.Key != key || c.now().Sub(e.Time) >= ttl {
+		return false, nil
+	}
+
+	err = json.Unmarshal(e.Response, response)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return true, nil
+}
+
+// Set stores the response of key, unless path is not cacheable.
+func (c *Cache) Set(key, path string, response any) error {
+	if !c.Cacheable(path) {
+		return nil
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry{Time: c.now().UTC(), Key: key, Response: raw})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	// Replace the file at once so that concurrent runs never read a partial response.
+	return atomicfile.WriteFile(c.path(key), data, 0o600)
+}
+
+// Clear removes all the cached responses and returns how many were removed.
+func (c *Cache) Clear() (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		err := os.Remove(filepath.Join(c.dir, e.Name()))
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// path returns the file path of the cached response of key.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,115 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRulesTTL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		expected time.Duration
+	}{
+		{
+			name:     "eco catalog",
+			path:     "/order/catalog/public/eco",
+			expected: 12 * time.Hour,
+		},
+		{
+			name:     "vps catalog",
+			path:     "/order/catalog/public/vps",
+			expected: 12 * time.Hour,
+		},
+		{
+			name:     "availabilities",
+			path:     "/dedicated/server/datacenter/availabilities",
+			expected: 0,
+		},
+		{
+			name:     "unknown path",
+			path:     "/me/order",
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DefaultRules.TTL(tc.path)
+			if got != tc.expected {
+				t.Errorf("TTL(%q) = %s, want %s", tc.path, got, tc.expected)
+			}
+		})
+	}
+
+	rules := Rules{
+		{Prefix: "/order/", TTL: time.Minute},
+		{Prefix: "/order/catalog/", TTL: time.Hour},
+	}
+	if got := rules.TTL("/order/catalog/public/eco"); got != time.Hour {
+		t.Errorf("longest prefix TTL = %s, want %s", got, time.Hour)
+	}
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	c, err := NewCache(t.TempDir(), DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now }
+
+	type response struct {
+		Plans []string `json:"plans"`
+	}
+
+	key := "https://eu.api.ovh.com/1.0/order/catalog/public/eco?ovhSubsidiary=FR"
+	path := "/order/catalog/public/eco"
+
+	var got *response
+	found, err := c.Get(key, path, &got)
+	if err != nil || found {
+		t.Fatalf("Get() of a missing response = %t, %v, want false", found, err)
+	}
+
+	saved := &response{Plans: []string{"24ska01", "24sk10"}}
+	err = c.Set(key, path, saved)
+	if err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	err = c.Set("https://eu.api.ovh.com/1.0/dedicated/server/datacenter/availabilities", "/dedicated/server/datacenter/availabilities", saved)
+	if err != nil {
+		t.Fatalf("Set() of an uncacheable path failed: %v", err)
+	}
+
+	found, err = c.Get(key, path, &got)
+	if err != nil || !found {
+		t.Fatalf("Get() = %t, %v, want true", found, err)
+	}
+	if diff := cmp.Diff(saved, got); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	found, err = c.WithRefresh().Get(key, path, &got)
+	if err != nil || found {
+		t.Errorf("Get() with refresh = %t, %v, want false", found, err)
+	}
+
+	now = now.Add(12 * time.Hour)
+	found, err = c.Get(key, path, &got)
+	if err != nil || found {
+		t.Errorf("Get() of an expired response = %t, %v, want false", found, err)
+	}
+
+	count, err := c.Clear()
+	if err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Clear() removed %d responses, want 1", count)
+	}
+}
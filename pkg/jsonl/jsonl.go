@@ -0,0 +1,106 @@
+// Package jsonl reads and writes JSON lines files, one JSON value per line.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/atomicfile"
+)
+
+// Read returns the values of the file at path, in file order. Empty lines are skipped.
+// A missing file is not an error and returns no values.
+func Read[T any](path string) ([]T, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var values []T
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var v T
+		err := json.Unmarshal(scanner.Bytes(), &v)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		values = append(values, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Append writes the values at the end of the file at path, creating it when missing.
+func Append[T any](path string, values ...T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = encode(f, values)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// Write replaces the content of the file at path with the values.
+// The file is written next to path and renamed over it.
+func Write[T any](path string, values []T) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	err = encode(&b, values)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// encode writes each value on its own line.
+func encode[T any](w io.Writer, values []T) error {
+	e := json.NewEncoder(w)
+	for _, v := range values {
+		err := e.Encode(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
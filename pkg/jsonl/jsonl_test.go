@@ -0,0 +1,69 @@
+package jsonl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadWrite(t *testing.T) {
+	type value struct {
+		Name string `json:"name"`
+	}
+
+	path := filepath.Join(t.TempDir(), "data", "values.jsonl")
+
+	got, err := Read[value](path)
+	if err != nil || got != nil {
+		t.Fatalf("Read() of a missing file = %v, %v, want no values", got, err)
+	}
+
+	err = Append(path, value{Name: "a"}, value{Name: "b"})
+	if err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+	err = Append(path, value{Name: "c"})
+	if err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+
+	got, err = Read[value](path)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if diff := cmp.Diff([]value{{Name: "a"}, {Name: "b"}, {Name: "c"}}, got); diff != "" {
+		t.Errorf("Read() after Append() mismatch (-want +got):\n%s", diff)
+	}
+
+	err = Write(path, []value{{Name: "d"}})
+	if err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	got, err = Read[value](path)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if diff := cmp.Diff([]value{{Name: "d"}}, got); diff != "" {
+		t.Errorf("Read() after Write() mismatch (-want +got):\n%s", diff)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Write() left %d files, want 1", len(entries))
+	}
+
+	err = os.WriteFile(path, []byte("{\"name\":\"e\"}\n\nnot json\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Read[value](path)
+	if err == nil {
+		t.Errorf("Read() of an invalid line succeeded, want an error")
+	}
+}
@@ -0,0 +1,41 @@
+package authentication
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Covers returns true when the rule grants the call, * in the rule path matches any characters.
+func (r CurrentCredentialRule) Covers(method, path string) bool {
+	if !strings.EqualFold(r.Method, method) {
+		return false
+	}
+
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(r.Path), `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(pattern, path)
+
+	return err == nil && matched
+}
+
+// Covers returns true when any of the credential rules grants the call.
+func (c CurrentCredentialResponse) Covers(method, path string) bool {
+	for _, rule := range c.Rules {
+		if rule.Covers(method, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MissingCalls returns the calls which are not granted by the credential rules.
+func (c CurrentCredentialResponse) MissingCalls(calls []RequiredCall) []RequiredCall {
+	var missing []RequiredCall
+	for _, call := range calls {
+		if !c.Covers(call.Method, call.Path) {
+			missing = append(missing, call)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,55 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMissingCalls(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    []CurrentCredentialRule
+		expected []string
+	}{
+		{
+			name:  "required rules",
+			rules: RequiredRules,
+		},
+		{
+			name:  "full access",
+			rules: []CurrentCredentialRule{{Method: "GET", Path: "/*"}, {Method: "POST", Path: "/*"}, {Method: "PUT", Path: "/*"}},
+		},
+		{
+			name: "read only orders",
+			rules: []CurrentCredentialRule{
+				{Method: "GET", Path: "/order/cart/*"},
+				{Method: "PUT", Path: "/order/cart/*"},
+				{Method: "POST", Path: "/order/cart/*/assign"},
+				{Method: "get", Path: "/me/order/*"},
+				{Method: "GET", Path: "/me/payment/method*"},
+			},
+			expected: []string{
+				"POST /order/cart/{cartId}/checkout",
+				"GET /me/order",
+				"POST /me/order/{orderId}/pay",
+				"POST /me/order/{orderId}/retraction",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := CurrentCredentialResponse{Rules: tc.rules}
+
+			var got []string
+			for _, call := range c.MissingCalls(RequiredCalls) {
+				got = append(got, call.Method+" "+call.Path)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("MissingCalls() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,5 +1,46 @@
 package authentication
 
+const (
+	CredentialStatusExpired           = "expired"
+	CredentialStatusPendingValidation = "pendingValidation"
+	CredentialStatusRefused           = "refused"
+	CredentialStatusValidated         = "validated"
+)
+
+var (
+	// RequiredRules are the access rules requested for the consumer key,
+	// they cover the order workflow, the order tracking and the payment of orders.
+	RequiredRules = []CurrentCredentialRule{
+		{Method: "GET", Path: "/order/cart/*"},
+		{Method: "POST", Path: "/order/cart/*"},
+		{Method: "PUT", Path: "/order/cart/*"},
+		{Method: "DELETE", Path: "/order/cart/*"},
+		{Method: "GET", Path: "/me/order"},
+		{Method: "GET", Path: "/me/order/*"},
+		{Method: "POST", Path: "/me/order/*"},
+		{Method: "GET", Path: "/me/payment/method"},
+		{Method: "GET", Path: "/me/payment/method/*"},
+		{Method: "GET", Path: "/me/api/application/*"},
+	}
+
+	// RequiredCalls are the authenticated API calls made by each feature, see CurrentCredentialResponse.MissingCalls.
+	RequiredCalls = []RequiredCall{
+		{Feature: "order", Method: "POST", Path: "/order/cart/{cartId}/assign"},
+		{Feature: "order", Method: "POST", Path: "/order/cart/{cartId}/checkout"},
+		{Feature: "order --preview", Method: "GET", Path: "/order/cart/{cartId}/checkout"},
+		{Feature: "watch --prepare-cart", Method: "PUT", Path: "/order/cart/{cartId}"},
+		{Feature: "order status", Method: "GET", Path: "/me/order/{orderId}"},
+		{Feature: "order status", Method: "GET", Path: "/me/order/{orderId}/status"},
+		{Feature: "order status", Method: "GET", Path: "/me/order/{orderId}/details"},
+		{Feature: "order status", Method: "GET", Path: "/me/order/{orderId}/details/{orderDetailId}"},
+		{Feature: "orders list", Method: "GET", Path: "/me/order"},
+		{Feature: "orders pay", Method: "GET", Path: "/me/payment/method"},
+		{Feature: "orders pay", Method: "GET", Path: "/me/payment/method/{paymentMethodId}"},
+		{Feature: "orders pay", Method: "POST", Path: "/me/order/{orderId}/pay"},
+		{Feature: "orders cancel-unpaid", Method: "POST", Path: "/me/order/{orderId}/retraction"},
+	}
+)
+
 type CurrentCredentialResponse struct {
 	ApplicationID int                     `json:"applicationId"`
 	Creation      string                  `json:"creation"`
@@ -15,3 +56,26 @@ type CurrentCredentialRule struct {
 	Method string `json:"method"`
 	Path   string `json:"path"`
 }
+
+// RequiredCall is an authenticated API call made by a feature.
+type RequiredCall struct {
+	Feature string
+	Method  string
+	Path    string
+}
+
+// Application is an OVH API application.
+type Application struct {
+	ApplicationID  int    `json:"applicationId"`
+	ApplicationKey string `json:"applicationKey"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Status         string `json:"status"`
+}
+
+// CredentialRequestResponse is a requested consumer key, valid once the user validated it at ValidationURL.
+type CredentialRequestResponse struct {
+	ConsumerKey   string `json:"consumerKey"`
+	State         string `json:"state"`
+	ValidationURL string `json:"validationUrl"`
+}
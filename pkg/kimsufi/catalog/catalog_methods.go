@@ -21,3 +21,20 @@ func (c Catalog) GetProduct(productName string) *Product {
 
 	return nil
 }
+
+// GetAddon returns the addon with the given plan code.
+func (c Catalog) GetAddon(planCode string) *Addon {
+	for _, addon := range c.Addons {
+		if addon.PlanCode == planCode {
+			return &addon
+		}
+	}
+
+	return nil
+}
+
+// GetFirstPrice does best effort to return the first price of the addon,
+// using the same criteria as Plan.GetFirstPrice.
+func (a Addon) GetFirstPrice() PlanPricing {
+	return Plan{Pricings: a.Pricings}.GetFirstPrice()
+}
@@ -137,6 +137,30 @@ func (p Plan) GetFirstPrice() PlanPricing {
 	return p.Pricings[0]
 }
 
+// GetSetupPrice returns the installation fee of the plan, preferring the one of the default price mode.
+// If plan has no installation fee, it returns an empty PlanPricing.
+func (p Plan) GetSetupPrice() PlanPricing {
+	var setup *PlanPricing
+	for _, price := range p.Pricings {
+		if !slices.Contains(price.Capacities, "installation") {
+			continue
+		}
+
+		if price.Mode == PriceModeDefault {
+			return price
+		}
+		if setup == nil {
+			setup = &price
+		}
+	}
+
+	if setup == nil {
+		return PlanPricing{}
+	}
+
+	return *setup
+}
+
 // FindPrice returns the price that matches the provided PlanPricing.
 func (p Plan) FindPrice(needle PlanPricing) *PlanPricing {
 	for _, price := range p.Pricings {
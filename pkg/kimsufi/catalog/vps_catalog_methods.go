@@ -31,6 +31,16 @@ func (p VPSPlan) GetCategory() string {
 	return "vps"
 }
 
+// GetConfiguration returns the first configuration that matches the provided name.
+func (p VPSPlan) GetConfiguration(name string) *VPSConfiguration {
+	for _, config := range p.Configurations {
+		if config.Name == name {
+			return &config
+		}
+	}
+	return nil
+}
+
 // GetFirstPrice returns the first suitable pricing entry for the VPS plan.
 // It follows similar logic to the regular servers, looking for monthly rental prices
 // and avoiding installation fees.
@@ -70,6 +80,12 @@ func (p VPSPlan) GetFirstPrice() VPSPricing {
 	return p.Pricings[0]
 }
 
+// GetFirstPrice returns the first suitable pricing entry for the VPS addon,
+// using the same criteria as VPSPlan.GetFirstPrice.
+func (a VPSAddon) GetFirstPrice() VPSPricing {
+	return VPSPlan{Pricings: a.Pricings}.GetFirstPrice()
+}
+
 // GetPrice returns the price as a float64, using the same divider as regular servers.
 // OVH stores prices as integers multiplied by 100,000,000 (8 decimal places).
 func (vp VPSPricing) GetPrice() float64 {
@@ -0,0 +1,190 @@
+package kimsufi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+// ListOrders returns the IDs of the orders of the user account placed between from and to, a zero time is not filtered on.
+// The Service must be authenticated.
+func (s *Service) ListOrders(from, to time.Time) ([]int, error) {
+	return s.ListOrdersCtx(s.context(), from, to)
+}
+
+// ListOrdersCtx is ListOrders with a context, the call is cancelled when ctx is done.
+func (s *Service) ListOrdersCtx(ctx context.Context, from, to time.Time) ([]int, error) {
+	u, err := url.Parse("/me/order")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	if !from.IsZero() {
+		q.Set("date.from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		q.Set("date.to", to.Format(time.RFC3339))
+	}
+	u.RawQuery = q.Encode()
+
+	var orderIDs []int
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &orderIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderIDs, nil
+}
+
+// GetOrder returns the order of the user account with the given ID.
+// The Service must be authenticated.
+func (s *Service) GetOrder(orderID int) (*kimsufiorder.MeOrder, error) {
+	return s.GetOrderCtx(s.context(), orderID)
+}
+
+// GetOrderCtx is GetOrder with a context, the call is cancelled when ctx is done.
+func (s *Service) GetOrderCtx(ctx context.Context, orderID int) (*kimsufiorder.MeOrder, error) {
+	u := fmt.Sprintf("/me/order/%d", orderID)
+
+	var resp kimsufiorder.MeOrder
+	err := s.call(ctx, http.MethodGet, u, nil, &resp, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetOrderStatus returns the status of the order, see kimsufiorder.OrderStatus* for known values.
+// The Service must be authenticated.
+func (s *Service) GetOrderStatus(orderID int) (string, error) {
+	return s.GetOrderStatusCtx(s.context(), orderID)
+}
+
+// GetOrderStatusCtx is GetOrderStatus with a context, the call is cancelled when ctx is done.
+func (s *Service) GetOrderStatusCtx(ctx context.Context, orderID int) (string, error) {
+	u := fmt.Sprintf("/me/order/%d/status", orderID)
+
+	var resp string
+	err := s.call(ctx, http.MethodGet, u, nil, &resp, true)
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+// GetOrderDetails returns the details of the order.
+// The Service must be authenticated.
+func (s *Service) GetOrderDetails(orderID int) ([]kimsufiorder.MeOrderDetail, error) {
+	return s.GetOrderDetailsCtx(s.context(), orderID)
+}
+
+// GetOrderDetailsCtx is GetOrderDetails with a context, the call is cancelled when ctx is done.
+func (s *Service) GetOrderDetailsCtx(ctx context.Context, orderID int) ([]kimsufiorder.MeOrderDetail, error) {
+	u := fmt.Sprintf("/me/order/%d/details", orderID)
+
+	var detailIDs []int
+	err := s.call(ctx, http.MethodGet, u, nil, &detailIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]kimsufiorder.MeOrderDetail, 0, len(detailIDs))
+	for _, detailID := range detailIDs {
+		var detail kimsufiorder.MeOrderDetail
+		err := s.call(ctx, http.MethodGet, fmt.Sprintf("%s/%d", u, detailID), nil, &detail, true)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
+
+// PayOrder pays the order with the registered payment method.
+// The Service must be authenticated.
+func (s *Service) PayOrder(orderID, paymentMethodID int) error {
+	return s.PayOrderCtx(s.context(), orderID, paymentMethodID)
+}
+
+// PayOrderCtx is PayOrder with a context, the call is cancelled when ctx is done.
+func (s *Service) PayOrderCtx(ctx context.Context, orderID, paymentMethodID int) error {
+	u := fmt.Sprintf("/me/order/%d/pay", orderID)
+
+	req := kimsufiorder.PayRequest{
+		PaymentMethod: kimsufiorder.PayRequestPaymentMethod{ID: paymentMethodID},
+	}
+
+	err := s.call(ctx, http.MethodPost, u, req, nil, true)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetractOrder requests the retraction of the order, see kimsufiorder.RetractionReason* for allowed reasons.
+// The Service must be authenticated.
+func (s *Service) RetractOrder(orderID int, reason, comment string) error {
+	return s.RetractOrderCtx(s.context(), orderID, reason, comment)
+}
+
+// RetractOrderCtx is RetractOrder with a context, the call is cancelled when ctx is done.
+func (s *Service) RetractOrderCtx(ctx context.Context, orderID int, reason, comment string) error {
+	u := fmt.Sprintf("/me/order/%d/retraction", orderID)
+
+	req := kimsufiorder.RetractionRequest{
+		Reason:  reason,
+		Comment: comment,
+	}
+
+	err := s.call(ctx, http.MethodPost, u, req, nil, true)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListPaymentMethods returns the valid payment methods registered on the user account.
+// The Service must be authenticated.
+func (s *Service) ListPaymentMethods() (kimsufiorder.PaymentMethods, error) {
+	return s.ListPaymentMethodsCtx(s.context())
+}
+
+// ListPaymentMethodsCtx is ListPaymentMethods with a context, the call is cancelled when ctx is done.
+func (s *Service) ListPaymentMethodsCtx(ctx context.Context) (kimsufiorder.PaymentMethods, error) {
+	u, err := url.Parse("/me/payment/method")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("status", kimsufiorder.PaymentMethodStatusValid)
+	u.RawQuery = q.Encode()
+
+	var paymentMethodIDs []int
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &paymentMethodIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentMethods := make(kimsufiorder.PaymentMethods, 0, len(paymentMethodIDs))
+	for _, paymentMethodID := range paymentMethodIDs {
+		var paymentMethod kimsufiorder.PaymentMethod
+		err := s.call(ctx, http.MethodGet, fmt.Sprintf("/me/payment/method/%d", paymentMethodID), nil, &paymentMethod, true)
+		if err != nil {
+			return nil, err
+		}
+		paymentMethods = append(paymentMethods, paymentMethod)
+	}
+
+	return paymentMethods, nil
+}
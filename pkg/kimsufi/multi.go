@@ -0,0 +1,97 @@
+package kimsufi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	kimsufiregion "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/region"
+)
+
+const (
+	// ParallelismDefault is the default number of concurrent calls made by FanOut.
+	ParallelismDefault = 4
+)
+
+// Target is an endpoint, and optionally one of its subsidiaries, queried by FanOut.
+type Target struct {
+	Endpoint   string `json:"endpoint"`
+	Subsidiary string `json:"subsidiary,omitempty"`
+}
+
+// TargetResult is the result of the call made for a Target.
+type TargetResult[T any] struct {
+	Target
+	Value T
+	Err   error
+}
+
+// WithContext returns a copy of m whose Services use ctx, see Service.WithContext.
+func (m MultiService) WithContext(ctx context.Context) MultiService {
+	newMultiService := make(MultiService, len(m))
+	for endpoint, s := range m {
+		newMultiService[endpoint] = *s.WithContext(ctx)
+	}
+
+	return newMultiService
+}
+
+// Targets returns every country of every region in region.AllowedRegions which endpoint has a Service in m.
+func (m MultiService) Targets() []Target {
+	var targets []Target
+	for _, region := range kimsufiregion.AllowedRegions {
+		if _, ok := m[region.Endpoint]; !ok {
+			continue
+		}
+
+		for _, country := range region.Countries {
+			targets = append(targets, Target{Endpoint: region.Endpoint, Subsidiary: country.Code})
+		}
+	}
+
+	return targets
+}
+
+// EndpointTargets returns the endpoint of every region in region.AllowedRegions which has a Service in m,
+// for calls which do not depend on the subsidiary.
+func (m MultiService) EndpointTargets() []Target {
+	var targets []Target
+	for _, region := range kimsufiregion.AllowedRegions {
+		if _, ok := m[region.Endpoint]; ok {
+			targets = append(targets, Target{Endpoint: region.Endpoint})
+		}
+	}
+
+	return targets
+}
+
+// FanOut calls fn for each target with the Service of its endpoint, making at most parallelism calls at once,
+// and returns the results in the targets order. A parallelism lower than 1 makes the calls one after the other.
+func FanOut[T any](m MultiService, targets []Target, parallelism int, fn func(*Service, Target) (T, error)) []TargetResult[T] {
+	results := make([]TargetResult[T], len(targets))
+	sem := make(chan struct{}, max(parallelism, 1))
+
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		results[i].Target = t
+
+		s := m.Endpoint(t.Endpoint)
+		if s == nil {
+			results[i].Err = fmt.Errorf("invalid endpoint %s", t.Endpoint)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].Value, results[i].Err = fn(s, t)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,74 @@
+package kimsufi
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMultiServiceTargets(t *testing.T) {
+	m := MultiService{"ovh-ca": Service{}}
+
+	expected := []Target{{Endpoint: "ovh-ca"}}
+	if diff := cmp.Diff(expected, m.EndpointTargets()); diff != "" {
+		t.Errorf("EndpointTargets() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, target := range m.Targets() {
+		if target.Endpoint != "ovh-ca" || target.Subsidiary == "" {
+			t.Errorf("Targets() returned unexpected target %+v", target)
+		}
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	m := MultiService{"ovh-eu": Service{}, "ovh-ca": Service{}}
+	targets := []Target{
+		{Endpoint: "ovh-eu", Subsidiary: "FR"},
+		{Endpoint: "ovh-ca", Subsidiary: "CA"},
+		{Endpoint: "ovh-eu", Subsidiary: "DE"},
+		{Endpoint: "ovh-us", Subsidiary: "US"},
+		{Endpoint: "ovh-ca", Subsidiary: "QC"},
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	results := FanOut(m, targets, 2, func(s *Service, target Target) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if target.Subsidiary == "QC" {
+			return "", errors.New("failed")
+		}
+		return target.Endpoint + "/" + target.Subsidiary, nil
+	})
+
+	var got []string
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r.Subsidiary)
+			continue
+		}
+		got = append(got, r.Value)
+	}
+
+	if diff := cmp.Diff([]string{"ovh-eu/FR", "ovh-ca/CA", "ovh-eu/DE"}, got); diff != "" {
+		t.Errorf("FanOut() values mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"US", "QC"}, failed); diff != "" {
+		t.Errorf("FanOut() failed targets mismatch (-want +got):\n%s", diff)
+	}
+	if n := maxInFlight.Load(); n > 2 {
+		t.Errorf("%d calls made at once, want at most 2", n)
+	}
+}
@@ -1,7 +1,9 @@
 package kimsufi
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -10,6 +12,11 @@ import (
 
 // CreateCart creates a new cart which will expire at the given time.
 func (s *Service) CreateCart(ovhSubsidiary string, expire time.Time) (*kimsufiorder.CartResponse, error) {
+	return s.CreateCartCtx(s.context(), ovhSubsidiary, expire)
+}
+
+// CreateCartCtx is CreateCart with a context, the call is cancelled when ctx is done.
+func (s *Service) CreateCartCtx(ctx context.Context, ovhSubsidiary string, expire time.Time) (*kimsufiorder.CartResponse, error) {
 	u := "/order/cart"
 
 	req := kimsufiorder.CartRequest{
@@ -20,7 +27,7 @@ func (s *Service) CreateCart(ovhSubsidiary string, expire time.Time) (*kimsufior
 	s.logger.Debugf("CreateCart request: %+#v", req)
 
 	var resp kimsufiorder.CartResponse
-	err := s.client.PostUnAuth(u, req, &resp)
+	err := s.call(ctx, http.MethodPost, u, req, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -28,8 +35,32 @@ func (s *Service) CreateCart(ovhSubsidiary string, expire time.Time) (*kimsufior
 	return &resp, nil
 }
 
+// UpdateCartExpire sets the time at which the cart will expire.
+// Assigned carts can only be updated with an authenticated Service and needAuth set.
+func (s *Service) UpdateCartExpire(cartID string, expire time.Time, needAuth bool) error {
+	return s.UpdateCartExpireCtx(s.context(), cartID, expire, needAuth)
+}
+
+// UpdateCartExpireCtx is UpdateCartExpire with a context, the call is cancelled when ctx is done.
+func (s *Service) UpdateCartExpireCtx(ctx context.Context, cartID string, expire time.Time, needAuth bool) error {
+	u := fmt.Sprintf("/order/cart/%s", cartID)
+
+	req := kimsufiorder.CartUpdateRequest{
+		Description: "kimsufi-notifier",
+		Expire:      expire.Format(time.RFC3339),
+	}
+	s.logger.Debugf("UpdateCartExpire request: %+#v", req)
+
+	return s.call(ctx, http.MethodPut, u, req, nil, needAuth)
+}
+
 // AddEcoItem adds an OVH eco item to the cart with the given planCode, quantity and duration, mode from priceConfig.
 func (s *Service) AddEcoItem(cartID, planCode string, quantity int, priceConfig kimsufiorder.EcoItemPriceConfig) (*kimsufiorder.EcoItemResponse, error) {
+	return s.AddEcoItemCtx(s.context(), cartID, planCode, quantity, priceConfig)
+}
+
+// AddEcoItemCtx is AddEcoItem with a context, the call is cancelled when ctx is done.
+func (s *Service) AddEcoItemCtx(ctx context.Context, cartID, planCode string, quantity int, priceConfig kimsufiorder.EcoItemPriceConfig) (*kimsufiorder.EcoItemResponse, error) {
 	u := fmt.Sprintf("/order/cart/%s/eco", cartID)
 
 	req := kimsufiorder.EcoItemRequest{
@@ -41,7 +72,7 @@ func (s *Service) AddEcoItem(cartID, planCode string, quantity int, priceConfig
 	s.logger.Debugf("AddEcoItem request: %+#v", req)
 
 	var resp kimsufiorder.EcoItemResponse
-	err := s.client.PostUnAuth(u, req, &resp)
+	err := s.call(ctx, http.MethodPost, u, req, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +82,11 @@ func (s *Service) AddEcoItem(cartID, planCode string, quantity int, priceConfig
 
 // AddVPSItem adds a VPS item to the cart with the given planCode, quantity and pricing config.
 func (s *Service) AddVPSItem(cartID, planCode string, quantity int, priceConfig kimsufiorder.VPSItemPriceConfig) (*kimsufiorder.VPSItemResponse, error) {
+	return s.AddVPSItemCtx(s.context(), cartID, planCode, quantity, priceConfig)
+}
+
+// AddVPSItemCtx is AddVPSItem with a context, the call is cancelled when ctx is done.
+func (s *Service) AddVPSItemCtx(ctx context.Context, cartID, planCode string, quantity int, priceConfig kimsufiorder.VPSItemPriceConfig) (*kimsufiorder.VPSItemResponse, error) {
 	u := fmt.Sprintf("/order/cart/%s/vps", cartID)
 
 	req := kimsufiorder.VPSItemRequest{
@@ -62,7 +98,7 @@ func (s *Service) AddVPSItem(cartID, planCode string, quantity int, priceConfig
 	s.logger.Debugf("AddVPSItem request: %+#v", req)
 
 	var resp kimsufiorder.VPSItemResponse
-	err := s.client.PostUnAuth(u, req, &resp)
+	err := s.call(ctx, http.MethodPost, u, req, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +108,11 @@ func (s *Service) AddVPSItem(cartID, planCode string, quantity int, priceConfig
 
 // GetEcoInfo returns information about an eco item in the cart.
 func (s *Service) GetEcoInfo(cartID, planCode string) (kimsufiorder.EcoItemInfos, error) {
+	return s.GetEcoInfoCtx(s.context(), cartID, planCode)
+}
+
+// GetEcoInfoCtx is GetEcoInfo with a context, the call is cancelled when ctx is done.
+func (s *Service) GetEcoInfoCtx(ctx context.Context, cartID, planCode string) (kimsufiorder.EcoItemInfos, error) {
 	u, err := url.Parse(fmt.Sprintf("/order/cart/%s/eco", cartID))
 	if err != nil {
 		return nil, err
@@ -82,7 +123,7 @@ func (s *Service) GetEcoInfo(cartID, planCode string) (kimsufiorder.EcoItemInfos
 	u.RawQuery = q.Encode()
 
 	var resp kimsufiorder.EcoItemInfos
-	err = s.client.GetUnAuth(u.String(), &resp)
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +133,11 @@ func (s *Service) GetEcoInfo(cartID, planCode string) (kimsufiorder.EcoItemInfos
 
 // GetEcoOptions returns the options for an eco item in the cart.
 func (s *Service) GetEcoOptions(cartID string, planCode string) (kimsufiorder.EcoItemOptions, error) {
+	return s.GetEcoOptionsCtx(s.context(), cartID, planCode)
+}
+
+// GetEcoOptionsCtx is GetEcoOptions with a context, the call is cancelled when ctx is done.
+func (s *Service) GetEcoOptionsCtx(ctx context.Context, cartID string, planCode string) (kimsufiorder.EcoItemOptions, error) {
 	u, err := url.Parse(fmt.Sprintf("/order/cart/%s/eco/options", cartID))
 	if err != nil {
 		return nil, err
@@ -102,7 +148,7 @@ func (s *Service) GetEcoOptions(cartID string, planCode string) (kimsufiorder.Ec
 	u.RawQuery = q.Encode()
 
 	var options kimsufiorder.EcoItemOptions
-	err = s.client.GetUnAuth(u.String(), &options)
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &options, false)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +159,11 @@ func (s *Service) GetEcoOptions(cartID string, planCode string) (kimsufiorder.Ec
 // ConfigureEcoItemOption configures the item options in the cart.
 // It finds the cheapest mandatory options and merges them into the user options.
 func (s *Service) ConfigureEcoItemOption(cartID string, itemID int, option kimsufiorder.Option, priceConfig kimsufiorder.EcoItemPriceConfig) error {
+	return s.ConfigureEcoItemOptionCtx(s.context(), cartID, itemID, option, priceConfig)
+}
+
+// ConfigureEcoItemOptionCtx is ConfigureEcoItemOption with a context, the call is cancelled when ctx is done.
+func (s *Service) ConfigureEcoItemOptionCtx(ctx context.Context, cartID string, itemID int, option kimsufiorder.Option, priceConfig kimsufiorder.EcoItemPriceConfig) error {
 	u := fmt.Sprintf("/order/cart/%s/eco/options", cartID)
 
 	req := kimsufiorder.EcoItemOptionRequest{
@@ -125,15 +176,20 @@ func (s *Service) ConfigureEcoItemOption(cartID string, itemID int, option kimsu
 	}
 
 	s.logger.Debugf("ConfigureItemOptions request: %+#v", req)
-	return s.client.PostUnAuth(u, req, nil)
+	return s.call(ctx, http.MethodPost, u, req, nil, false)
 }
 
 // GetItemRequiredConfiguration returns the required configuration options for an item in the cart.
 func (s *Service) GetItemRequiredConfiguration(cartID string, itemID int) ([]kimsufiorder.ItemConfiguration, error) {
+	return s.GetItemRequiredConfigurationCtx(s.context(), cartID, itemID)
+}
+
+// GetItemRequiredConfigurationCtx is GetItemRequiredConfiguration with a context, the call is cancelled when ctx is done.
+func (s *Service) GetItemRequiredConfigurationCtx(ctx context.Context, cartID string, itemID int) ([]kimsufiorder.ItemConfiguration, error) {
 	u := fmt.Sprintf("/order/cart/%s/item/%d/requiredConfiguration", cartID, itemID)
 
 	var resp []kimsufiorder.ItemConfiguration
-	err := s.client.GetUnAuth(u, &resp)
+	err := s.call(ctx, http.MethodGet, u, nil, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -143,11 +199,16 @@ func (s *Service) GetItemRequiredConfiguration(cartID string, itemID int) ([]kim
 
 // ConfigureItem configures an item in the cart with the given configurations.
 func (s *Service) AddItemConfiguration(cartID string, itemID int, configuration kimsufiorder.ItemConfigurationRequest) (*kimsufiorder.ItemConfigurationResponse, error) {
+	return s.AddItemConfigurationCtx(s.context(), cartID, itemID, configuration)
+}
+
+// AddItemConfigurationCtx is AddItemConfiguration with a context, the call is cancelled when ctx is done.
+func (s *Service) AddItemConfigurationCtx(ctx context.Context, cartID string, itemID int, configuration kimsufiorder.ItemConfigurationRequest) (*kimsufiorder.ItemConfigurationResponse, error) {
 	u := fmt.Sprintf("/order/cart/%s/item/%d/configuration", cartID, itemID)
 
 	var resp kimsufiorder.ItemConfigurationResponse
 	s.logger.Debugf("ConfigureItem request: %+#v", configuration)
-	err := s.client.PostUnAuth(u, configuration, &resp)
+	err := s.call(ctx, http.MethodPost, u, configuration, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -157,16 +218,26 @@ func (s *Service) AddItemConfiguration(cartID string, itemID int, configuration
 
 // RemoveItemConfiguration removes a configuration from an item in the cart.
 func (s *Service) RemoveItemConfiguration(cartID string, itemID, configurationID int) error {
+	return s.RemoveItemConfigurationCtx(s.context(), cartID, itemID, configurationID)
+}
+
+// RemoveItemConfigurationCtx is RemoveItemConfiguration with a context, the call is cancelled when ctx is done.
+func (s *Service) RemoveItemConfigurationCtx(ctx context.Context, cartID string, itemID, configurationID int) error {
 	u := fmt.Sprintf("/order/cart/%s/item/%d/configuration/%d", cartID, itemID, configurationID)
 
-	return s.client.DeleteUnAuth(u, nil)
+	return s.call(ctx, http.MethodDelete, u, nil, nil, false)
 }
 
 // AssignCart assigns the cart to the user's account.
 func (s *Service) AssignCart(cartID string) error {
+	return s.AssignCartCtx(s.context(), cartID)
+}
+
+// AssignCartCtx is AssignCart with a context, the call is cancelled when ctx is done.
+func (s *Service) AssignCartCtx(ctx context.Context, cartID string) error {
 	u := fmt.Sprintf("/order/cart/%s/assign", cartID)
 
-	err := s.client.Post(u, nil, nil)
+	err := s.call(ctx, http.MethodPost, u, nil, nil, true)
 	if err != nil {
 		return err
 	}
@@ -174,9 +245,32 @@ func (s *Service) AssignCart(cartID string) error {
 	return nil
 }
 
+// GetCheckoutPreview returns the order the cart checkout would place, with its details and prices, without placing it.
+func (s *Service) GetCheckoutPreview(cartID string) (*kimsufiorder.CheckoutResponse, error) {
+	return s.GetCheckoutPreviewCtx(s.context(), cartID)
+}
+
+// GetCheckoutPreviewCtx is GetCheckoutPreview with a context, the call is cancelled when ctx is done.
+func (s *Service) GetCheckoutPreviewCtx(ctx context.Context, cartID string) (*kimsufiorder.CheckoutResponse, error) {
+	u := fmt.Sprintf("/order/cart/%s/checkout", cartID)
+
+	var resp kimsufiorder.CheckoutResponse
+	err := s.call(ctx, http.MethodGet, u, nil, &resp, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 // CheckoutCart checks out the cart to place the order.
 // If autoPay is true, the order will be paid automatically using the preferred payment method.
 func (s *Service) CheckoutCart(cartID string, autoPay bool) (*kimsufiorder.CheckoutResponse, error) {
+	return s.CheckoutCartCtx(s.context(), cartID, autoPay)
+}
+
+// CheckoutCartCtx is CheckoutCart with a context, the call is cancelled when ctx is done.
+func (s *Service) CheckoutCartCtx(ctx context.Context, cartID string, autoPay bool) (*kimsufiorder.CheckoutResponse, error) {
 	u := fmt.Sprintf("/order/cart/%s/checkout", cartID)
 
 	req := kimsufiorder.CheckoutRequest{
@@ -186,7 +280,7 @@ func (s *Service) CheckoutCart(cartID string, autoPay bool) (*kimsufiorder.Check
 	s.logger.Debugf("CheckoutCart request: %+#v", req)
 
 	var resp kimsufiorder.CheckoutResponse
-	err := s.client.Post(u, req, &resp)
+	err := s.call(ctx, http.MethodPost, u, req, &resp, true)
 	if err != nil {
 		return nil, err
 	}
@@ -218,6 +312,11 @@ func (s *Service) GenerateItemAutoConfigurations(itemConfigurationOptions []kims
 
 // GetVPSInfo returns information about VPS items available in the cart.
 func (s *Service) GetVPSInfo(cartID, planCode string) (kimsufiorder.VPSItemInfos, error) {
+	return s.GetVPSInfoCtx(s.context(), cartID, planCode)
+}
+
+// GetVPSInfoCtx is GetVPSInfo with a context, the call is cancelled when ctx is done.
+func (s *Service) GetVPSInfoCtx(ctx context.Context, cartID, planCode string) (kimsufiorder.VPSItemInfos, error) {
 	u, err := url.Parse(fmt.Sprintf("/order/cart/%s/vps", cartID))
 	if err != nil {
 		return nil, err
@@ -230,7 +329,7 @@ func (s *Service) GetVPSInfo(cartID, planCode string) (kimsufiorder.VPSItemInfos
 	u.RawQuery = q.Encode()
 
 	var resp kimsufiorder.VPSItemInfos
-	err = s.client.GetUnAuth(u.String(), &resp)
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &resp, false)
 	if err != nil {
 		return nil, err
 	}
@@ -240,6 +339,11 @@ func (s *Service) GetVPSInfo(cartID, planCode string) (kimsufiorder.VPSItemInfos
 
 // GetVPSOptions returns the options for VPS items in the cart.
 func (s *Service) GetVPSOptions(cartID string, planCode string) (kimsufiorder.VPSItemOptions, error) {
+	return s.GetVPSOptionsCtx(s.context(), cartID, planCode)
+}
+
+// GetVPSOptionsCtx is GetVPSOptions with a context, the call is cancelled when ctx is done.
+func (s *Service) GetVPSOptionsCtx(ctx context.Context, cartID string, planCode string) (kimsufiorder.VPSItemOptions, error) {
 	u, err := url.Parse(fmt.Sprintf("/order/cart/%s/vps/options", cartID))
 	if err != nil {
 		return nil, err
@@ -252,7 +356,7 @@ func (s *Service) GetVPSOptions(cartID string, planCode string) (kimsufiorder.VP
 	u.RawQuery = q.Encode()
 
 	var resp kimsufiorder.VPSItemOptions
-	err = s.client.GetUnAuth(u.String(), &resp)
+	err = s.call(ctx, http.MethodGet, u.String(), nil, &resp, false)
 	if err != nil {
 		return nil, err
 	}
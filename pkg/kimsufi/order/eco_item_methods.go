@@ -65,3 +65,16 @@ func (e EcoItemInfo) firstPriceConfig() *EcoItemPriceConfig {
 
 	return nil
 }
+
+// GetRenewPrice returns the renewal price matching the price config, or nil if there is none.
+func (e EcoItemInfo) GetRenewPrice(priceConfig EcoItemPriceConfig) *EcoItemInfoPrice {
+	for _, price := range e.Prices {
+		if price.Duration == priceConfig.Duration &&
+			price.PricingMode == priceConfig.PricingMode &&
+			slices.Contains(price.Capacities, PricingCapacityRenew) {
+			return &price
+		}
+	}
+
+	return nil
+}
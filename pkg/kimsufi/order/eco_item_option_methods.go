@@ -86,6 +86,19 @@ func (i EcoItemOption) GetPriceByConfig(priceConfig EcoItemPriceConfig) *EcoItem
 	return nil
 }
 
+// GetRenewPriceByConfig returns the first renewal price that matches the provided EcoItemPriceConfig.
+// Prices without capacities are considered renewal prices.
+func (i EcoItemOption) GetRenewPriceByConfig(priceConfig EcoItemPriceConfig) *EcoItemOptionPrice {
+	for _, price := range i.Prices {
+		if price.Duration == priceConfig.Duration && price.PricingMode == priceConfig.PricingMode &&
+			(len(price.Capacities) == 0 || slices.Contains(price.Capacities, PricingCapacityRenew)) {
+			return &price
+		}
+	}
+
+	return nil
+}
+
 // ToOptions converts EcoItemOptions to Options.
 func (i EcoItemOptions) ToOptions() Options {
 	var options []Option
@@ -19,10 +19,11 @@ type Option struct {
 }
 
 type EcoItemOptionPrice struct {
-	Duration      string `json:"duration"`
-	PricingMode   string `json:"pricingMode"`
-	PriceInUcents int    `json:"priceInUcents"`
-	Price         Price  `json:"price"`
+	Capacities    []string `json:"capacities"`
+	Duration      string   `json:"duration"`
+	PricingMode   string   `json:"pricingMode"`
+	PriceInUcents int      `json:"priceInUcents"`
+	Price         Price    `json:"price"`
 }
 
 // EcoItemOptionRequest represents the request to add an option
@@ -0,0 +1,23 @@
+package order
+
+// GetDefault returns the default payment method, or nil if there is none.
+func (p PaymentMethods) GetDefault() *PaymentMethod {
+	for _, m := range p {
+		if m.Default {
+			return &m
+		}
+	}
+
+	return nil
+}
+
+// GetByID returns the payment method with the given ID, or nil if not found.
+func (p PaymentMethods) GetByID(id int) *PaymentMethod {
+	for _, m := range p {
+		if m.PaymentMethodID == id {
+			return &m
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package order
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OrderStatusCancelled          = "cancelled"
+	OrderStatusCancelling         = "cancelling"
+	OrderStatusChecking           = "checking"
+	OrderStatusDelivered          = "delivered"
+	OrderStatusDelivering         = "delivering"
+	OrderStatusDocumentsRequested = "documentsRequested"
+	OrderStatusNotPaid            = "notPaid"
+	OrderStatusUnknown            = "unknown"
+)
+
+var (
+	OrderStatuses = []string{
+		OrderStatusCancelled,
+		OrderStatusCancelling,
+		OrderStatusChecking,
+		OrderStatusDelivered,
+		OrderStatusDelivering,
+		OrderStatusDocumentsRequested,
+		OrderStatusNotPaid,
+		OrderStatusUnknown,
+	}
+
+	RetractionReasons = []string{
+		RetractionReasonCompetitor,
+		RetractionReasonDifficulty,
+		RetractionReasonExpensive,
+		RetractionReasonOther,
+		RetractionReasonPerformance,
+		RetractionReasonReliability,
+		RetractionReasonUnused,
+	}
+)
+
+// MeOrder represents an order of the user account.
+type MeOrder struct {
+	OrderID         int        `json:"orderId"`
+	Date            time.Time  `json:"date"`
+	ExpirationDate  *time.Time `json:"expirationDate"`
+	RetractionDate  *time.Time `json:"retractionDate"`
+	PDFURL          string     `json:"pdfUrl"`
+	URL             string     `json:"url"`
+	PriceWithTax    Price      `json:"priceWithTax"`
+	PriceWithoutTax Price      `json:"priceWithoutTax"`
+	Tax             Price      `json:"tax"`
+}
+
+// MeOrderDetail represents a line of an order of the user account.
+type MeOrderDetail struct {
+	DetailID    int         `json:"detailId"`
+	Description string      `json:"description"`
+	DetailType  string      `json:"detailType"`
+	Domain      string      `json:"domain"`
+	Quantity    json.Number `json:"quantity"`
+	TotalPrice  Price       `json:"totalPrice"`
+	UnitPrice   Price       `json:"unitPrice"`
+}
+
+const (
+	PaymentMethodStatusValid = "VALID"
+
+	RetractionReasonCompetitor  = "competitor"
+	RetractionReasonDifficulty  = "difficulty"
+	RetractionReasonExpensive   = "expensive"
+	RetractionReasonOther       = "other"
+	RetractionReasonPerformance = "performance"
+	RetractionReasonReliability = "reliability"
+	RetractionReasonUnused      = "unused"
+)
+
+// PaymentMethod represents a payment method registered on the user account.
+type PaymentMethod struct {
+	PaymentMethodID int    `json:"paymentMethodId"`
+	PaymentType     string `json:"paymentType"`
+	Label           string `json:"label"`
+	Description     string `json:"description"`
+	Default         bool   `json:"default"`
+	Status          string `json:"status"`
+}
+
+// PaymentMethods represents a list of payment methods.
+type PaymentMethods []PaymentMethod
+
+// PayRequest represents the request to pay an order.
+type PayRequest struct {
+	PaymentMethod PayRequestPaymentMethod `json:"paymentMethod"`
+}
+
+// PayRequestPaymentMethod identifies the payment method used to pay an order.
+type PayRequestPaymentMethod struct {
+	ID int `json:"id"`
+}
+
+// RetractionRequest represents the request to retract an order.
+type RetractionRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment,omitempty"`
+}
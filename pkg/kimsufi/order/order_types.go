@@ -1,5 +1,7 @@
 package order
 
+import "encoding/json"
+
 const (
 	// PriceDuration P1M represents a duration of 1 month.
 	// There are other durations like P0D for installation price only, P1Y for 1 year, etc...
@@ -13,8 +15,16 @@ const (
 
 	QuantityDefault = 1
 
-	ConfigurationLabelDatacenter = "dedicated_datacenter"
-	ConfigurationLabelRegion     = "region"
+	ConfigurationLabelDatacenter    = "dedicated_datacenter"
+	ConfigurationLabelVPSDatacenter = "vps_datacenter"
+	ConfigurationLabelRegion        = "region"
+
+	// DetailTypeDuration is the type of the order details billing the ordered duration.
+	DetailTypeDuration = "DURATION"
+	// DetailTypeInstallation is the type of the order details billing the setup fee.
+	DetailTypeInstallation = "INSTALLATION"
+	// DetailTypeRenew is the type of the order details billing a renewal.
+	DetailTypeRenew = "RENEW"
 )
 
 // CartRequest represents the request to create a cart.
@@ -24,6 +34,12 @@ type CartRequest struct {
 	OvhSubsidiary string `json:"ovhSubsidiary"`
 }
 
+// CartUpdateRequest represents the request to update a cart.
+type CartUpdateRequest struct {
+	Description string `json:"description"`
+	Expire      string `json:"expire"`
+}
+
 // CartResponse represents the response of a cart creation.
 type CartResponse struct {
 	CartID string `json:"cartId"`
@@ -40,6 +56,7 @@ type CheckoutResponse struct {
 	OrderID   int                `json:"orderId,omitempty"`
 	URL       string             `json:"url,omitempty"`
 	Contracts []CheckoutContract `json:"contracts,omitempty"`
+	Details   []CheckoutDetail   `json:"details,omitempty"`
 	Prices    CheckoutPrices     `json:"prices,omitempty"`
 }
 
@@ -49,6 +66,19 @@ type CheckoutContract struct {
 	URL     string `json:"url"`
 }
 
+// CheckoutDetail represents a line of an order.
+type CheckoutDetail struct {
+	CartItemID          int         `json:"cartItemID"`
+	Description         string      `json:"description"`
+	DetailType          string      `json:"detailType"`
+	Domain              string      `json:"domain"`
+	Quantity            json.Number `json:"quantity"`
+	OriginalTotalPrice  Price       `json:"originalTotalPrice"`
+	ReductionTotalPrice Price       `json:"reductionTotalPrice"`
+	TotalPrice          Price       `json:"totalPrice"`
+	UnitPrice           Price       `json:"unitPrice"`
+}
+
 type CheckoutPrices struct {
 	OriginalWithoutTax Price `json:"originalWithoutTax,omitempty"`
 	Reduction          Price `json:"reduction,omitempty"`
@@ -102,3 +102,15 @@ func (options VPSItemOptions) ToOptions() Options {
 	}
 	return result
 }
+
+// GetRenewPrice returns the renewal price matching the config, or nil if there is none.
+func (info *VPSItemInfo) GetRenewPrice(config VPSItemPriceConfig) *VPSItemInfoPrice {
+	for _, price := range info.Prices {
+		if price.Duration == config.Duration &&
+			price.PricingMode == config.PricingMode &&
+			slices.Contains(price.Capacities, PricingCapacityRenew) {
+			return &price
+		}
+	}
+	return nil
+}
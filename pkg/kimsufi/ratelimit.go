@@ -0,0 +1,132 @@
+package kimsufi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the token bucket limiting the calls made to an endpoint.
+type RateLimit struct {
+	// Rate is the number of calls allowed per second, 0 disables the limit.
+	Rate float64
+	// Burst is the number of calls allowed at once.
+	Burst int
+}
+
+// DefaultRateLimit is the rate limit of the endpoints, see SetRateLimit.
+var DefaultRateLimit = RateLimit{
+	Rate:  10,
+	Burst: 20,
+}
+
+var (
+	// rateLimiters are the rate limiters of each endpoint URL, shared by all its Services.
+	rateLimiters   = make(map[string]*rateLimiter)
+	rateLimitersMu sync.Mutex
+)
+
+// rateLimiter is a token bucket.
+type rateLimiter struct {
+	mu sync.Mutex
+	RateLimit
+	tokens float64
+	last   time.Time
+	// pausedUntil delays all calls after the endpoint asked to retry later.
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// SetRateLimit sets the rate limit of every endpoint, the limiters already in use are updated.
+func SetRateLimit(l RateLimit) {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	DefaultRateLimit = l
+	for _, r := range rateLimiters {
+		r.mu.Lock()
+		r.RateLimit = l
+		r.tokens = min(r.tokens, float64(l.Burst))
+		r.mu.Unlock()
+	}
+}
+
+// getRateLimiter returns the rate limiter of the endpoint URL.
+func getRateLimiter(endpoint string) *rateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	r, ok := rateLimiters[endpoint]
+	if !ok {
+		r = newRateLimiter(DefaultRateLimit, time.Now)
+		rateLimiters[endpoint] = r
+	}
+
+	return r
+}
+
+// newRateLimiter creates a full token bucket.
+func newRateLimiter(l RateLimit, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		RateLimit: l,
+		tokens:    float64(l.Burst),
+		last:      now(),
+		now:       now,
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (r *rateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var pause time.Duration
+	if r.pausedUntil.After(now) {
+		pause = r.pausedUntil.Sub(now)
+	}
+
+	if r.Rate <= 0 {
+		return pause
+	}
+
+	r.tokens = min(r.tokens+now.Sub(r.last).Seconds()*r.Rate, float64(max(r.Burst, 1)))
+	r.last = now
+	r.tokens--
+
+	var wait time.Duration
+	if r.tokens < 0 {
+		wait = time.Duration(-r.tokens / r.Rate * float64(time.Second))
+	}
+
+	return max(wait, pause)
+}
+
+// pause delays the calls made in the next d.
+func (r *rateLimiter) pause(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	until := r.now().Add(d)
+	if until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+}
+
+// wait blocks until a call is allowed or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	d := r.reserve()
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package kimsufi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	r := newRateLimiter(RateLimit{Rate: 2, Burst: 2}, func() time.Time { return now })
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		pause   time.Duration
+		wait    time.Duration
+	}{
+		{name: "first burst call", wait: 0},
+		{name: "second burst call", wait: 0},
+		{name: "bucket empty", wait: 500 * time.Millisecond},
+		{name: "refilled", advance: 2 * time.Second, wait: 0},
+		{name: "paused", pause: 3 * time.Second, wait: 3 * time.Second},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if step.pause > 0 {
+			r.pause(step.pause)
+		}
+
+		got := r.reserve()
+		if got != step.wait {
+			t.Errorf("%s: reserve() = %s, want %s", step.name, got, step.wait)
+		}
+	}
+}
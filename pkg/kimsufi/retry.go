@@ -0,0 +1,137 @@
+package kimsufi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ovh/go-ovh/ovh"
+)
+
+// RetryPolicy configures how failed API calls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a call, 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on each retry.
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between attempts,
+	// a call asked to be retried after a longer delay by Retry-After is not retried.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of the Services created with NewService.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// Backoff returns the delay before the attempt following the given one, starting at 1.
+// It is the Retry-After delay when set, or an exponential delay with jitter otherwise:
+// a random duration between half and all of BaseDelay * 2^(attempt-1), capped at MaxDelay.
+func (p RetryPolicy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	d := p.BaseDelay << min(attempt-1, 30)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// IsRetryableError checks if a call made with the method and failing with err may be retried.
+// Idempotent calls are retried on network errors, 429 and 5xx responses.
+// Other calls, like a cart checkout, are only retried on 429 responses, which reject the request
+// before it is processed. A 5xx response, even a 503 from a gateway, may come after the order
+// was placed, so retrying could place it twice.
+func IsRetryableError(method string, err error) bool {
+	var ovhAPIError *ovh.APIError
+	if errors.As(err, &ovhAPIError) {
+		switch {
+		case ovhAPIError.Code == http.StatusTooManyRequests:
+			return true
+		case ovhAPIError.Code >= http.StatusInternalServerError:
+			return isIdempotent(method)
+		default:
+			return false
+		}
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return isIdempotent(method)
+	}
+
+	return false
+}
+
+// isIdempotent checks if calls made with the HTTP method can be repeated without side effects.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterKey is the context key of the Retry-After delay of a call.
+type retryAfterKey struct{}
+
+// retryAfterTransport records the Retry-After header of the responses
+// in the duration held by the request context, see withRetryAfter.
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+// withRetryAfter returns a context in which the Retry-After delay of the call is recorded into d.
+func withRetryAfter(ctx context.Context, d *time.Duration) context.Context {
+	return context.WithValue(ctx, retryAfterKey{}, d)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, ok := req.Context().Value(retryAfterKey{}).(*time.Duration); ok {
+		*d = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+
+	return resp, nil
+}
+
+// parseRetryAfter returns the delay of a Retry-After header value, given in seconds or as an HTTP date.
+// It returns 0 when the value is empty or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,179 @@
+package kimsufi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ovh/go-ovh/ovh"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "get rate limited",
+			method:   http.MethodGet,
+			err:      &ovh.APIError{Code: http.StatusTooManyRequests},
+			expected: true,
+		},
+		{
+			name:     "get server error",
+			method:   http.MethodGet,
+			err:      fmt.Errorf("wrapped: %w", &ovh.APIError{Code: http.StatusBadGateway}),
+			expected: true,
+		},
+		{
+			name:     "get not found",
+			method:   http.MethodGet,
+			err:      &ovh.APIError{Code: http.StatusNotFound},
+			expected: false,
+		},
+		{
+			name:     "checkout rate limited",
+			method:   http.MethodPost,
+			err:      &ovh.APIError{Code: http.StatusTooManyRequests},
+			expected: true,
+		},
+		{
+			name:     "checkout unavailable",
+			method:   http.MethodPost,
+			err:      &ovh.APIError{Code: http.StatusServiceUnavailable},
+			expected: false,
+		},
+		{
+			name:     "checkout server error",
+			method:   http.MethodPost,
+			err:      &ovh.APIError{Code: http.StatusInternalServerError},
+			expected: false,
+		},
+		{
+			name:     "other error",
+			method:   http.MethodGet,
+			err:      errors.New("invalid response"),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsRetryableError(tc.method, tc.err)
+			if got != tc.expected {
+				t.Errorf("IsRetryableError(%s, %v) = %t, want %t", tc.method, tc.err, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	testCases := []struct {
+		attempt    int
+		retryAfter time.Duration
+		min        time.Duration
+		max        time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{attempt: 10, min: 2500 * time.Millisecond, max: 5 * time.Second},
+		{attempt: 1, retryAfter: 3 * time.Second, min: 3 * time.Second, max: 3 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		for i := 0; i < 100; i++ {
+			got := p.Backoff(tc.attempt, tc.retryAfter)
+			if got < tc.min || got > tc.max {
+				t.Fatalf("Backoff(%d, %s) = %s, want between %s and %s", tc.attempt, tc.retryAfter, got, tc.min, tc.max)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "12", expected: 12 * time.Second},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), expected: time.Minute},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+		{value: "soon", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		got := parseRetryAfter(tc.value, now)
+		if got != tc.expected {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tc.value, got, tc.expected)
+		}
+	}
+}
+
+func TestServiceRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if n < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"Too many requests"}`)
+			return
+		}
+		fmt.Fprint(w, `{"orderId":1}`)
+	}))
+	defer server.Close()
+
+	s, err := newService(server.URL, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = s.WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+	var resp struct {
+		OrderID int `json:"orderId"`
+	}
+	err = s.call(context.Background(), http.MethodPost, "/order/cart/1/checkout", nil, &resp, false)
+	if err != nil {
+		t.Fatalf("call() failed: %v", err)
+	}
+	if resp.OrderID != 1 || calls.Load() != 3 {
+		t.Errorf("call() made %d calls and returned order %d, want 3 calls and order 1", calls.Load(), resp.OrderID)
+	}
+
+	var unavailable atomic.Int32
+	unavailableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unavailable.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"message":"Service unavailable"}`)
+	}))
+	defer unavailableServer.Close()
+
+	u, err := newService(unavailableServer.URL, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u = u.WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	err = u.call(context.Background(), http.MethodPost, "/order/cart/1/checkout", nil, &resp, false)
+	if err == nil || unavailable.Load() != 1 {
+		t.Errorf("checkout call() made %d calls and returned %v, want 1 call and an error", unavailable.Load(), err)
+	}
+
+	calls.Store(0)
+	s = s.WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	err = s.call(context.Background(), http.MethodGet, "/order/cart/1", nil, &resp, false)
+	if !IsRetryableError(http.MethodGet, err) || calls.Load() != 2 {
+		t.Errorf("call() made %d calls and returned %v, want 2 calls and a rate limit error", calls.Load(), err)
+	}
+}
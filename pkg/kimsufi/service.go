@@ -1,6 +1,7 @@
 package kimsufi
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"net/http"
@@ -8,26 +9,42 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/ovh/go-ovh/ovh"
 	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/httpcache"
 	kimsufiauthentication "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/authentication"
 	kimsufiavailability "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/availability"
 	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
 )
 
+// diskCache is the on-disk cache of the Services created after SetDiskCache.
+var diskCache *httpcache.Cache
+
+// SetDiskCache sets the on-disk cache used by the Services created afterwards, nil disables it.
+func SetDiskCache(c *httpcache.Cache) {
+	diskCache = c
+}
+
 // MultiService is a map of OVH endpoints to Services.
 type MultiService map[string]Service
 
 // Service is a wrapper around ovh.Client
-// with optional caching and logging.
+// with optional in-memory and on-disk caching, and logging.
+// Its calls are rate limited per endpoint and retried following its RetryPolicy.
+// Methods without a context use the Service context, see WithContext.
 type Service struct {
-	cache  *cache.Cache
-	client *ovh.Client
-	logger *Logger
+	cache   *cache.Cache
+	disk    *httpcache.Cache
+	client  *ovh.Client
+	ctx     context.Context
+	logger  *Logger
+	limiter *rateLimiter
+	retry   RetryPolicy
 }
 
 // NewMultiService creates a new MultiService
@@ -67,22 +84,66 @@ func NewService(endpoint string, logger *logrus.Logger, c *cache.Cache) (*Servic
 		return nil, fmt.Errorf("invalid endpoint %s", endpoint)
 	}
 
-	client, err := ovh.NewClient(e, "none", "none", "none")
+	return newService(e, logger, c)
+}
+
+// newService creates a new Service for the given endpoint URL.
+func newService(endpointURL string, logger *logrus.Logger, c *cache.Cache) (*Service, error) {
+	client, err := ovh.NewClient(endpointURL, "none", "none", "none")
 	if err != nil {
 		return nil, err
 	}
 
 	s := &Service{
-		cache:  c,
-		client: client,
-		logger: NewRequestLogger(logger),
+		cache:   c,
+		disk:    diskCache,
+		client:  client,
+		logger:  NewRequestLogger(logger),
+		limiter: getRateLimiter(endpointURL),
+		retry:   DefaultRetryPolicy,
 	}
 
 	client.Logger = s.logger
+	client.Client.Transport = &retryAfterTransport{}
 
 	return s, nil
 }
 
+// WithContext returns a copy of the Service whose methods without a context are cancelled when ctx is done.
+func (s *Service) WithContext(ctx context.Context) *Service {
+	newService := *s
+	newService.ctx = ctx
+
+	return &newService
+}
+
+// context returns the Service context, context.Background when not set.
+func (s *Service) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+
+	return s.ctx
+}
+
+// WithRetryPolicy returns a copy of the Service retrying its calls following p.
+func (s *Service) WithRetryPolicy(p RetryPolicy) *Service {
+	newService := *s
+	newService.retry = p
+
+	return &newService
+}
+
+// WithRefresh returns a copy of the Service which ignores the responses cached on disk and replaces them.
+func (s *Service) WithRefresh() *Service {
+	newService := *s
+	if s.disk != nil {
+		newService.disk = s.disk.WithRefresh()
+	}
+
+	return &newService
+}
+
 // GetOVHEndpoints returns a list of OVH endpoints.
 // It keeps only the ones starting with "ovh-".
 func GetOVHEndpoints() []string {
@@ -100,6 +161,11 @@ func GetOVHEndpoints() []string {
 // options is a map of additional query parameters.
 // see https://eu.api.ovh.com/console/?section=%2Fdedicated%2Fserver&branch=v1#get-/dedicated/server/datacenter/availabilities
 func (s *Service) GetAvailabilities(datacenters []string, planCode string, options map[string]string) (*kimsufiavailability.Availabilities, error) {
+	return s.GetAvailabilitiesCtx(s.context(), datacenters, planCode, options)
+}
+
+// GetAvailabilitiesCtx is GetAvailabilities with a context, the call is cancelled when ctx is done.
+func (s *Service) GetAvailabilitiesCtx(ctx context.Context, datacenters []string, planCode string, options map[string]string) (*kimsufiavailability.Availabilities, error) {
 	path := "/dedicated/server/datacenter/availabilities"
 
 	queryArgs := make(map[string]string)
@@ -114,7 +180,7 @@ func (s *Service) GetAvailabilities(datacenters []string, planCode string, optio
 	}
 
 	var availabilities *kimsufiavailability.Availabilities
-	err := s.request(http.MethodGet, path, queryArgs, nil, &availabilities, false)
+	err := s.request(ctx, http.MethodGet, path, queryArgs, nil, &availabilities, false)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +192,11 @@ func (s *Service) GetAvailabilities(datacenters []string, planCode string, optio
 // ovhSubsidiary is the country code to filter on, given in a two-letter format.
 // see https://eu.api.ovh.com/console/?section=%2Forder&branch=v1#get-/order/catalog/public/eco
 func (s *Service) ListServers(ovhSubsidiary string) (*kimsuficatalog.Catalog, error) {
+	return s.ListServersCtx(s.context(), ovhSubsidiary)
+}
+
+// ListServersCtx is ListServers with a context, the call is cancelled when ctx is done.
+func (s *Service) ListServersCtx(ctx context.Context, ovhSubsidiary string) (*kimsuficatalog.Catalog, error) {
 	path := "/order/catalog/public/eco"
 
 	queryArgs := map[string]string{
@@ -133,7 +204,7 @@ func (s *Service) ListServers(ovhSubsidiary string) (*kimsuficatalog.Catalog, er
 	}
 
 	var catalog *kimsuficatalog.Catalog
-	err := s.request(http.MethodGet, path, queryArgs, nil, &catalog, false)
+	err := s.request(ctx, http.MethodGet, path, queryArgs, nil, &catalog, false)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +216,11 @@ func (s *Service) ListServers(ovhSubsidiary string) (*kimsuficatalog.Catalog, er
 // ovhSubsidiary is the country code to filter on, given in a two-letter format.
 // see https://eu.api.ovh.com/console/?section=%2Forder&branch=v1#get-/order/catalog/public/vps
 func (s *Service) ListVPSServers(ovhSubsidiary string) (*kimsuficatalog.VPSCatalog, error) {
+	return s.ListVPSServersCtx(s.context(), ovhSubsidiary)
+}
+
+// ListVPSServersCtx is ListVPSServers with a context, the call is cancelled when ctx is done.
+func (s *Service) ListVPSServersCtx(ctx context.Context, ovhSubsidiary string) (*kimsuficatalog.VPSCatalog, error) {
 	path := "/order/catalog/public/vps"
 
 	queryArgs := map[string]string{
@@ -152,7 +228,7 @@ func (s *Service) ListVPSServers(ovhSubsidiary string) (*kimsuficatalog.VPSCatal
 	}
 
 	var catalog *kimsuficatalog.VPSCatalog
-	err := s.request(http.MethodGet, path, queryArgs, nil, &catalog, false)
+	err := s.request(ctx, http.MethodGet, path, queryArgs, nil, &catalog, false)
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +242,11 @@ func (s *Service) ListVPSServers(ovhSubsidiary string) (*kimsuficatalog.VPSCatal
 // os is optional OS filter.
 // see https://ca.api.ovh.com/console/?section=%2Fvps&branch=v1#get-/vps/order/rule/datacenter
 func (s *Service) GetVPSAvailabilities(planCode, ovhSubsidiary, os string) (*kimsufiavailability.VPSAvailabilities, error) {
+	return s.GetVPSAvailabilitiesCtx(s.context(), planCode, ovhSubsidiary, os)
+}
+
+// GetVPSAvailabilitiesCtx is GetVPSAvailabilities with a context, the call is cancelled when ctx is done.
+func (s *Service) GetVPSAvailabilitiesCtx(ctx context.Context, planCode, ovhSubsidiary, os string) (*kimsufiavailability.VPSAvailabilities, error) {
 	path := "/vps/order/rule/datacenter"
 
 	queryArgs := map[string]string{
@@ -177,7 +258,7 @@ func (s *Service) GetVPSAvailabilities(planCode, ovhSubsidiary, os string) (*kim
 	}
 
 	var availabilities *kimsufiavailability.VPSAvailabilities
-	err := s.request(http.MethodGet, path, queryArgs, nil, &availabilities, false)
+	err := s.request(ctx, http.MethodGet, path, queryArgs, nil, &availabilities, false)
 	if err != nil {
 		return nil, err
 	}
@@ -187,9 +268,14 @@ func (s *Service) GetVPSAvailabilities(planCode, ovhSubsidiary, os string) (*kim
 
 // GetAuthDetails performs a test API request to check if the client is authenticated.
 func (s *Service) GetAuthDetails() error {
+	return s.GetAuthDetailsCtx(s.context())
+}
+
+// GetAuthDetailsCtx is GetAuthDetails with a context, the call is cancelled when ctx is done.
+func (s *Service) GetAuthDetailsCtx(ctx context.Context) error {
 	path := "/auth/details"
 
-	err := s.client.Get(path, nil)
+	err := s.call(ctx, http.MethodGet, path, nil, nil, true)
 	if err != nil {
 		return err
 	}
@@ -198,10 +284,34 @@ func (s *Service) GetAuthDetails() error {
 }
 
 func (s *Service) GetCurrentCredential() (*kimsufiauthentication.CurrentCredentialResponse, error) {
+	return s.GetCurrentCredentialCtx(s.context())
+}
+
+// GetCurrentCredentialCtx is GetCurrentCredential with a context, the call is cancelled when ctx is done.
+func (s *Service) GetCurrentCredentialCtx(ctx context.Context) (*kimsufiauthentication.CurrentCredentialResponse, error) {
 	path := "/auth/currentCredential"
 
 	var resp kimsufiauthentication.CurrentCredentialResponse
-	err := s.client.Get(path, &resp)
+	err := s.call(ctx, http.MethodGet, path, nil, &resp, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetApplication returns the OVH API application with the given ID.
+// The Service must be authenticated.
+func (s *Service) GetApplication(applicationID int) (*kimsufiauthentication.Application, error) {
+	return s.GetApplicationCtx(s.context(), applicationID)
+}
+
+// GetApplicationCtx is GetApplication with a context, the call is cancelled when ctx is done.
+func (s *Service) GetApplicationCtx(ctx context.Context, applicationID int) (*kimsufiauthentication.Application, error) {
+	path := fmt.Sprintf("/me/api/application/%d", applicationID)
+
+	var resp kimsufiauthentication.Application
+	err := s.call(ctx, http.MethodGet, path, nil, &resp, true)
 	if err != nil {
 		return nil, err
 	}
@@ -209,6 +319,35 @@ func (s *Service) GetCurrentCredential() (*kimsufiauthentication.CurrentCredenti
 	return &resp, nil
 }
 
+// RequestCredential requests a new consumer key granted the rules,
+// it must be validated by the user at the returned validation URL before use.
+// The Service must be created with the application key and secret, see WithAuth.
+func (s *Service) RequestCredential(rules []kimsufiauthentication.CurrentCredentialRule) (*kimsufiauthentication.CredentialRequestResponse, error) {
+	return s.RequestCredentialCtx(s.context(), rules)
+}
+
+// RequestCredentialCtx is RequestCredential with a context, the call is cancelled when ctx is done.
+func (s *Service) RequestCredentialCtx(ctx context.Context, rules []kimsufiauthentication.CurrentCredentialRule) (*kimsufiauthentication.CredentialRequestResponse, error) {
+	req := s.client.NewCkRequest()
+	for _, rule := range rules {
+		req.AddRule(rule.Method, rule.Path)
+	}
+
+	var state ovh.CkValidationState
+	err := s.call(ctx, http.MethodPost, "/auth/credential", req, &state, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &kimsufiauthentication.CredentialRequestResponse{
+		ConsumerKey:   state.ConsumerKey,
+		State:         state.State,
+		ValidationURL: state.ValidationURL,
+	}
+
+	return resp, nil
+}
+
 // WithAuth returns a new authenticated Service with the given credentials.
 func (s *Service) WithAuth(appKey, appSecret, consumerKey string) (*Service, error) {
 	authClient, err := ovh.NewClient(s.client.Endpoint(), appKey, appSecret, consumerKey)
@@ -216,21 +355,70 @@ func (s *Service) WithAuth(appKey, appSecret, consumerKey string) (*Service, err
 		return nil, err
 	}
 
+	authClient.Logger = s.logger
+	authClient.Client.Transport = &retryAfterTransport{}
+
 	newService := &Service{
-		cache:  s.cache,
-		logger: s.logger,
-		client: authClient,
+		cache:   s.cache,
+		disk:    s.disk,
+		logger:  s.logger,
+		client:  authClient,
+		ctx:     s.ctx,
+		limiter: s.limiter,
+		retry:   s.retry,
 	}
 
 	return newService, nil
 }
 
+// call performs an API call, once allowed by the endpoint rate limiter.
+// Failed calls are retried following the Service RetryPolicy, see IsRetryableError.
+// A call asked to be retried later with Retry-After also delays the other calls to the endpoint.
+// The call is cancelled, and not retried, when ctx is done.
+func (s *Service) call(ctx context.Context, method, path string, body any, response any, needAuth bool) error {
+	for attempt := 1; ; attempt++ {
+		if s.limiter != nil {
+			err := s.limiter.wait(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		var retryAfter time.Duration
+		err := s.client.CallAPIWithContext(withRetryAfter(ctx, &retryAfter), method, path, body, response, needAuth)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= s.retry.MaxAttempts || ctx.Err() != nil || !IsRetryableError(method, err) {
+			return err
+		}
+		if retryAfter > s.retry.MaxDelay {
+			s.logger.Debugf("%s %s: not retried, asked to retry after %s: %v", method, path, retryAfter, err)
+			return err
+		}
+		if retryAfter > 0 && s.limiter != nil {
+			s.limiter.pause(retryAfter)
+		}
+
+		delay := s.retry.Backoff(attempt, retryAfter)
+		s.logger.Debugf("%s %s: attempt %d/%d failed, retrying in %s: %v", method, path, attempt, s.retry.MaxAttempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
 // request performs an API request.
-// this is a wrapper around ovh.Client.CallAPI, it allows for caching when set on the Service.
+// this is a wrapper around ovh.Client.CallAPIWithContext, see call, it allows for caching when set on the Service.
+// Unauthenticated GET responses are also kept on disk when the path is cacheable, see SetDiskCache.
 // path and queryArgs are combined to form the request URL.
 // method, body, response, and needAuth are passed as is.
 // response must be a pointer.
-func (s *Service) request(method, path string, queryArgs map[string]string, body any, response any, needAuth bool) error {
+func (s *Service) request(ctx context.Context, method, path string, queryArgs map[string]string, body any, response any, needAuth bool) error {
 	// Ensure response is a pointer
 	rv := reflect.ValueOf(response)
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
@@ -258,16 +446,39 @@ func (s *Service) request(method, path string, queryArgs map[string]string, body
 		s.logger.Tracef("cache hit: %s", cacheKey)
 		ce := reflect.ValueOf(cacheEntry)
 		rv.Elem().Set(ce.Elem())
+		return nil
+	}
+
+	disk := s.disk
+	if method != http.MethodGet || needAuth {
+		disk = nil
+	}
+
+	if disk != nil {
+		found, err = disk.Get(cacheKey, u.Path, response)
+		if err != nil {
+			s.logger.Debugf("disk cache: %v", err)
+		}
+	}
+	if found {
+		s.logger.Tracef("disk cache hit: %s", cacheKey)
 	} else {
 		s.logger.Tracef("cache miss: %s", cacheKey)
-		err = s.client.CallAPI(method, u.String(), body, response, needAuth)
+		err = s.call(ctx, method, u.String(), body, response, needAuth)
 		if err != nil {
 			return err
 		}
-		if s.cache != nil {
-			s.cache.Set(cacheKey, response, cache.DefaultExpiration)
+		if disk != nil {
+			err = disk.Set(cacheKey, u.Path, response)
+			if err != nil {
+				s.logger.Warnf("failed to write disk cache: %v", err)
+			}
 		}
 	}
 
+	if s.cache != nil {
+		s.cache.Set(cacheKey, response, cache.DefaultExpiration)
+	}
+
 	return nil
 }
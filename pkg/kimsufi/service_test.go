@@ -1,9 +1,19 @@
 package kimsufi
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/httpcache"
 )
 
 func TestNewMultiService(t *testing.T) {
@@ -96,3 +106,93 @@ func TestServiceLogger(t *testing.T) {
 		t.Error("expected logger to be set")
 	}
 }
+
+func TestServiceContext(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s, err := newService(server.URL, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = s.GetAvailabilitiesCtx(ctx, nil, "24ska01", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetAvailabilitiesCtx() with a cancelled context returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = s.WithContext(ctx).ListServers("FR")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ListServers() with a cancelled Service context returned %v, want %v", err, context.Canceled)
+	}
+
+	if n := calls.Load(); n != 0 {
+		t.Errorf("%d calls made with a cancelled context, want none", n)
+	}
+}
+
+func TestServiceDiskCache(t *testing.T) {
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		if strings.HasSuffix(r.URL.Path, "/availabilities") {
+			fmt.Fprint(w, "[]")
+			return
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	c, err := httpcache.NewCache(t.TempDir(), httpcache.DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetDiskCache(c)
+	defer SetDiskCache(nil)
+
+	for range 2 {
+		s, err := newService(server.URL, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = s.ListServers("FR")
+		if err != nil {
+			t.Fatalf("ListServers() failed: %v", err)
+		}
+
+		_, err = s.GetAvailabilities(nil, "24ska01", nil)
+		if err != nil {
+			t.Fatalf("GetAvailabilities() failed: %v", err)
+		}
+	}
+
+	// Authenticated services share the disk cache.
+	s, err := newService(server.URL, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = s.WithAuth("key", "secret", "consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ListServers("FR")
+	if err != nil {
+		t.Fatalf("ListServers() failed: %v", err)
+	}
+
+	expected := map[string]int{
+		"/order/catalog/public/eco":                   1,
+		"/dedicated/server/datacenter/availabilities": 2,
+	}
+	if diff := cmp.Diff(expected, calls); diff != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -44,3 +44,9 @@ func IntervalToDuration(interval int, unit string) string {
 
 	return "P0D"
 }
+
+// IsVPSPlanCode returns true if the plan code belongs to the VPS catalog.
+// VPS plan codes typically start with "vps-" or "s1-".
+func IsVPSPlanCode(planCode string) bool {
+	return strings.HasPrefix(planCode, "vps-") || strings.HasPrefix(planCode, "s1-")
+}
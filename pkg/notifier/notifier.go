@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+)
+
+// Multi is a Notifier which delivers events to every notifier it holds.
+type Multi []Notifier
+
+// Notify delivers the event to all notifiers, it returns the joined errors.
+func (m Multi) Notify(e Event) error {
+	var errs []error
+
+	for _, n := range m {
+		err := n.Notify(e)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NotifyAll delivers every event, it returns the joined errors.
+func NotifyAll(n Notifier, events []Event) error {
+	var errs []error
+
+	for _, e := range events {
+		err := n.Notify(e)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Webhook is a Notifier which posts events as JSON to an URL.
+type Webhook struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhook creates a new Webhook notifier posting to url.
+func NewWebhook(url string) *Webhook {
+	w := &Webhook{
+		URL: url,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+		},
+	}
+
+	return w
+}
+
+// Notify posts the event to the webhook URL.
+func (w *Webhook) Notify(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s: unexpected status %s: %s", w.URL, resp.Status, bytes.TrimSpace(b))
+	}
+
+	return nil
+}
+
+// Printer is a Notifier which prints events for the user.
+type Printer struct {
+	w io.Writer
+}
+
+// NewPrinter creates a new Printer notifier writing to w.
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{
+		w: w,
+	}
+}
+
+// Notify prints the event title, followed by its message indented.
+func (p *Printer) Notify(e Event) error {
+	_, err := fmt.Fprintf(p.w, "> %s\n", e.Title)
+	if err != nil {
+		return err
+	}
+
+	if e.Message == "" {
+		return nil
+	}
+
+	for _, line := range strings.Split(e.Message, "\n") {
+		_, err := fmt.Fprintf(p.w, "  %s\n", line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// New creates a Notifier which prints events to w and posts them to every webhook URL.
+func New(w io.Writer, webhooks []string) Notifier {
+	m := Multi{
+		NewPrinter(w),
+	}
+
+	for _, url := range webhooks {
+		m = append(m, NewWebhook(url))
+	}
+
+	return m
+}
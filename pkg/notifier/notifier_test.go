@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(e Event) error {
+	return errors.New("failed")
+}
+
+func TestWebhook(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&received)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	e := Event{
+		Time:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Type:  "test",
+		Title: "hello",
+	}
+
+	err := NewWebhook(server.URL).Notify(e)
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if received.Title != e.Title || received.Type != e.Type || !received.Time.Equal(e.Time) {
+		t.Errorf("expected %+v, got %+v", e, received)
+	}
+}
+
+func TestWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL).Notify(Event{})
+	if err == nil {
+		t.Error("expected error on unexpected status")
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var count int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+	}))
+	defer server.Close()
+
+	m := Multi{failingNotifier{}, NewWebhook(server.URL)}
+
+	err := m.Notify(Event{})
+	if err == nil {
+		t.Error("expected error from failing notifier")
+	}
+	if count != 1 {
+		t.Errorf("expected webhook to be notified once, got %d", count)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	var b bytes.Buffer
+	p := NewPrinter(&b)
+
+	err := p.Notify(Event{Title: "Ordered 24ska01 in rbx", Message: "cheapest candidate\nhttps://example.com/order"})
+	if err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	err = p.Notify(Event{Title: "24ska01 is available in gra"})
+	if err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+
+	expected := "> Ordered 24ska01 in rbx\n  cheapest candidate\n  https://example.com/order\n> 24ska01 is available in gra\n"
+	if b.String() != expected {
+		t.Errorf("Notify() printed %q, want %q", b.String(), expected)
+	}
+}
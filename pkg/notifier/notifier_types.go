@@ -0,0 +1,19 @@
+package notifier
+
+import "time"
+
+// Event is a notification emitted by a command.
+// Type is a dotted identifier of the kind of event (e.g. catalog.plan.added),
+// Data holds the structured payload of the event.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Title   string    `json:"title"`
+	Message string    `json:"message,omitempty"`
+	Data    any       `json:"data,omitempty"`
+}
+
+// Notifier delivers events.
+type Notifier interface {
+	Notify(e Event) error
+}
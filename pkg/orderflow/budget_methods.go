@@ -0,0 +1,70 @@
+package orderflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+// NewPrices returns the prices of the order from its checkout preview.
+// The price of the ordered duration is divided by its number of months, given by priceDuration (e.g. P12M),
+// and by the quantity of items, to get the monthly price of one item.
+func NewPrices(preview *kimsufiorder.CheckoutResponse, priceDuration string, quantity int) Prices {
+	p := Prices{
+		Total:        preview.Prices.WithTax.Value,
+		CurrencyCode: preview.Prices.WithTax.CurrencyCode,
+	}
+
+	for _, d := range preview.Details {
+		switch d.DetailType {
+		case kimsufiorder.DetailTypeDuration, kimsufiorder.DetailTypeRenew:
+			p.Monthly += d.TotalPrice.Value
+		case kimsufiorder.DetailTypeInstallation:
+			p.Setup += d.TotalPrice.Value
+		}
+	}
+
+	months, ok := durationMonths(priceDuration)
+	if !ok || months <= 0 {
+		months = 1
+	}
+	p.Monthly /= float64(months * max(quantity, 1))
+
+	return p
+}
+
+// String returns the prices in a human readable format.
+func (p Prices) String() string {
+	return fmt.Sprintf("monthly %.2f %s, setup %.2f %s, total %.2f %s", p.Monthly, p.CurrencyCode, p.Setup, p.CurrencyCode, p.Total, p.CurrencyCode)
+}
+
+// IsZero returns true when the budget has no limit.
+func (b Budget) IsZero() bool {
+	return b.MaxMonthly <= 0 && b.MaxSetup <= 0 && b.MaxTotal <= 0 && len(b.Currencies) == 0
+}
+
+// Check returns an error wrapping ErrBudgetExceeded with every exceeded limit, or nil when the prices are within the budget.
+func (b Budget) Check(p Prices) error {
+	var reasons []string
+
+	if len(b.Currencies) > 0 && !slices.ContainsFunc(b.Currencies, func(c string) bool { return strings.EqualFold(c, p.CurrencyCode) }) {
+		reasons = append(reasons, fmt.Sprintf("currency %s is not allowed (allowed values: %s)", p.CurrencyCode, strings.Join(b.Currencies, ", ")))
+	}
+	if b.MaxMonthly > 0 && p.Monthly > b.MaxMonthly {
+		reasons = append(reasons, fmt.Sprintf("monthly price %.2f %s exceeds %.2f", p.Monthly, p.CurrencyCode, b.MaxMonthly))
+	}
+	if b.MaxSetup > 0 && p.Setup > b.MaxSetup {
+		reasons = append(reasons, fmt.Sprintf("setup fee %.2f %s exceeds %.2f", p.Setup, p.CurrencyCode, b.MaxSetup))
+	}
+	if b.MaxTotal > 0 && p.Total > b.MaxTotal {
+		reasons = append(reasons, fmt.Sprintf("total price %.2f %s exceeds %.2f", p.Total, p.CurrencyCode, b.MaxTotal))
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrBudgetExceeded, strings.Join(reasons, ", "))
+}
@@ -0,0 +1,111 @@
+package orderflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+func TestNewPrices(t *testing.T) {
+	preview := &kimsufiorder.CheckoutResponse{
+		Details: []kimsufiorder.CheckoutDetail{
+			{DetailType: kimsufiorder.DetailTypeDuration, TotalPrice: kimsufiorder.Price{Value: 10}},
+			{DetailType: kimsufiorder.DetailTypeInstallation, TotalPrice: kimsufiorder.Price{Value: 4.99}},
+			{DetailType: kimsufiorder.DetailTypeDuration, TotalPrice: kimsufiorder.Price{Value: 2}},
+			{DetailType: "OTHER", TotalPrice: kimsufiorder.Price{Value: 100}},
+		},
+		Prices: kimsufiorder.CheckoutPrices{
+			WithTax: kimsufiorder.Price{Value: 21.58, CurrencyCode: "EUR"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		priceDuration string
+		quantity      int
+		expected      Prices
+	}{
+		{
+			name:          "one month",
+			priceDuration: "P1M",
+			quantity:      1,
+			expected:      Prices{Monthly: 12, Setup: 4.99, Total: 21.58, CurrencyCode: "EUR"},
+		},
+		{
+			name:     "default duration",
+			expected: Prices{Monthly: 12, Setup: 4.99, Total: 21.58, CurrencyCode: "EUR"},
+		},
+		{
+			name:          "one year",
+			priceDuration: "P1Y",
+			quantity:      1,
+			expected:      Prices{Monthly: 1, Setup: 4.99, Total: 21.58, CurrencyCode: "EUR"},
+		},
+		{
+			name:          "several items",
+			priceDuration: "P12M",
+			quantity:      2,
+			expected:      Prices{Monthly: 0.5, Setup: 4.99, Total: 21.58, CurrencyCode: "EUR"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewPrices(preview, tc.priceDuration, tc.quantity)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("NewPrices() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBudgetCheck(t *testing.T) {
+	prices := Prices{Monthly: 12.99, Setup: 4.99, Total: 21.58, CurrencyCode: "EUR"}
+
+	testCases := []struct {
+		name          string
+		budget        Budget
+		expectedError string
+	}{
+		{
+			name:   "no limit",
+			budget: Budget{},
+		},
+		{
+			name:   "within limits",
+			budget: Budget{MaxMonthly: 15, MaxSetup: 5, MaxTotal: 25, Currencies: []string{"eur"}},
+		},
+		{
+			name:          "monthly exceeded",
+			budget:        Budget{MaxMonthly: 10},
+			expectedError: "budget exceeded: monthly price 12.99 EUR exceeds 10.00",
+		},
+		{
+			name:          "several limits exceeded",
+			budget:        Budget{MaxSetup: 1, MaxTotal: 20, Currencies: []string{"USD"}},
+			expectedError: "budget exceeded: currency EUR is not allowed (allowed values: USD), setup fee 4.99 EUR exceeds 1.00, total price 21.58 EUR exceeds 20.00",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.budget.Check(prices)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Check() failed: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrBudgetExceeded) {
+				t.Fatalf("Check() = %v, want ErrBudgetExceeded", err)
+			}
+			if err.Error() != tc.expectedError {
+				t.Errorf("Check() = %q, want %q", err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package orderflow
+
+import (
+	"errors"
+)
+
+var (
+	// ErrBudgetExceeded is returned when the cart prices exceed the budget, the cart is not checked out.
+	ErrBudgetExceeded = errors.New("budget exceeded")
+)
+
+// Budget limits the prices of an order, zero values are not limited.
+type Budget struct {
+	// MaxMonthly is the maximum price per month of one item, without tax.
+	MaxMonthly float64
+	// MaxSetup is the maximum setup fee, without tax.
+	MaxSetup float64
+	// MaxTotal is the maximum total price of the order, with tax.
+	MaxTotal float64
+	// Currencies are the allowed currency codes.
+	Currencies []string
+}
+
+// Prices are the prices of an order, see NewPrices.
+type Prices struct {
+	// Monthly is the price per month of one item, without tax.
+	Monthly float64
+	// Setup is the setup fee, without tax.
+	Setup float64
+	// Total is the total price of the order, with tax.
+	Total        float64
+	CurrencyCode string
+}
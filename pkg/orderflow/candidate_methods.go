@@ -0,0 +1,254 @@
+package orderflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+)
+
+// ParseCandidate parses a candidate from a comma separated list of key=value fields, list values are separated by +.
+// e.g. plan=24sk50,datacenters=rbx+gra,options=memory=ram-32g-ecc-2133-24sk50+storage=softraid-2x2000sa-24sk50
+func ParseCandidate(value string) (Candidate, error) {
+	var c Candidate
+
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key, v, _ := strings.Cut(field, "=")
+		switch key {
+		case "plan", "planCode":
+			c.PlanCode = v
+		case "datacenters", "datacenter":
+			c.Datacenters = splitList(v)
+		case "options", "option":
+			c.ItemOptions = splitList(v)
+		default:
+			return Candidate{}, fmt.Errorf("invalid candidate %q: unknown field %q", value, key)
+		}
+	}
+
+	if c.PlanCode == "" {
+		return Candidate{}, fmt.Errorf("invalid candidate %q: missing plan", value)
+	}
+
+	return c, nil
+}
+
+// ParseCandidates parses candidates, see ParseCandidate.
+func ParseCandidates(values []string) (Candidates, error) {
+	var candidates Candidates
+	for _, value := range values {
+		c, err := ParseCandidate(value)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// PlanCodes returns the distinct plan codes of the candidates.
+func (c Candidates) PlanCodes() []string {
+	var planCodes []string
+	for _, candidate := range c {
+		if !slices.Contains(planCodes, candidate.PlanCode) {
+			planCodes = append(planCodes, candidate.PlanCode)
+		}
+	}
+
+	return planCodes
+}
+
+// Preferred returns the available datacenters accepted by the candidate, in its order of preference.
+// All available datacenters are accepted when the candidate has no datacenters.
+func (c Candidate) Preferred(available []string) []string {
+	if len(c.Datacenters) == 0 {
+		return available
+	}
+
+	var preferred []string
+	for _, dc := range c.Datacenters {
+		if slices.ContainsFunc(available, func(a string) bool { return strings.EqualFold(a, dc) }) {
+			preferred = append(preferred, dc)
+		}
+	}
+
+	return preferred
+}
+
+// String returns the candidate plan code and datacenters.
+func (c Candidate) String() string {
+	return fmt.Sprintf("%s in %s", c.PlanCode, datacenterList(c.Datacenters))
+}
+
+// Evaluate checks the live availability of each candidate.
+func Evaluate(k *kimsufi.Service, subsidiary string, candidates Candidates) Evaluations {
+	var evaluations Evaluations
+	for i, c := range candidates {
+		e := Evaluation{Rank: i + 1, Candidate: c}
+
+		var available []string
+		if kimsufi.IsVPSPlanCode(c.PlanCode) {
+			availabilities, err := k.GetVPSAvailabilities(c.PlanCode, subsidiary, "")
+			if err != nil {
+				e.Err = err
+			} else {
+				available = availabilities.GetAvailableDatacenterCodes()
+			}
+		} else {
+			availabilities, err := k.GetAvailabilities(c.Datacenters, c.PlanCode, nil)
+			if err != nil && !kimsufi.IsAvailabilityNotFoundError(err) {
+				e.Err = err
+			} else if err == nil {
+				available = availabilities.GetAvailableDatacenters().Codes()
+			}
+		}
+		e.Available = c.Preferred(available)
+
+		evaluations = append(evaluations, e)
+	}
+
+	return evaluations
+}
+
+// IsAvailable returns true when the candidate can be ordered.
+func (e Evaluation) IsAvailable() bool {
+	return e.Err == nil && len(e.Available) > 0
+}
+
+// Reason explains the evaluation.
+func (e Evaluation) Reason() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("failed: %v", e.Err)
+	case len(e.Available) == 0:
+		return fmt.Sprintf("not available in %s", datacenterList(e.Candidate.Datacenters))
+	default:
+		return fmt.Sprintf("available in %s", strings.Join(e.Available, ", "))
+	}
+}
+
+// String returns the candidate, its rank and the evaluation reason.
+func (e Evaluation) String() string {
+	return fmt.Sprintf("%s (rank %d) %s", e.Candidate.PlanCode, e.Rank, e.Reason())
+}
+
+// Explain explains why the i-th candidate was ordered in the datacenter.
+func (e Evaluations) Explain(i int, datacenter string) string {
+	winner := e[i]
+	if i == 0 {
+		return fmt.Sprintf("ordered %s (rank %d) in %s: most preferred candidate", winner.Candidate.PlanCode, winner.Rank, datacenter)
+	}
+
+	var reasons []string
+	for _, previous := range e[:i] {
+		reasons = append(reasons, previous.String())
+	}
+
+	return fmt.Sprintf("ordered %s (rank %d) in %s: %s", winner.Candidate.PlanCode, winner.Rank, datacenter, strings.Join(reasons, "; "))
+}
+
+// String explains every evaluation.
+func (e Evaluations) String() string {
+	var reasons []string
+	for _, evaluation := range e {
+		reasons = append(reasons, evaluation.String())
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// OrderCandidates checks the live availability of the candidates and orders the best available ones, see OrderEvaluated.
+func OrderCandidates(k *kimsufi.Service, o Options, candidates Candidates, total int) ([]*Result, error) {
+	return OrderEvaluated(k, o, Evaluate(k, o.Subsidiary, candidates), total)
+}
+
+// OrderEvaluated orders the available candidates in order of preference until total items are ordered,
+// each order being of o.Quantity items, the last one of the remaining items only. A candidate is ordered
+// again while its orders succeed, the next one is tried otherwise. The Result Reason explains why its candidate was chosen.
+func OrderEvaluated(k *kimsufi.Service, o Options, evaluations Evaluations, total int) ([]*Result, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("total quantity must be positive")
+	}
+
+	evaluations = slices.Clone(evaluations)
+	for _, e := range evaluations {
+		o.progress(Event{Step: StepCandidateEvaluated, PlanCode: e.Candidate.PlanCode, Datacenters: e.Available, Reason: e.String()})
+	}
+
+	var results []*Result
+	var errs []error
+	ordered := 0
+	for i := range evaluations {
+		if ordered >= total {
+			break
+		}
+
+		e := &evaluations[i]
+		if !e.IsAvailable() {
+			continue
+		}
+
+		co := o
+		co.PlanCode = e.Candidate.PlanCode
+		co.Datacenters = e.Available
+		if len(e.Candidate.ItemOptions) > 0 {
+			co.ItemOptions = e.Candidate.ItemOptions
+		}
+
+		for ordered < total {
+			co.Quantity = min(max(o.Quantity, 1), total-ordered)
+			result, err := Order(k, co)
+			if err != nil {
+				e.Err = err
+				errs = append(errs, fmt.Errorf("%s: %w", e.Candidate.PlanCode, err))
+				break
+			}
+
+			datacenter := result.Datacenter
+			if result.DryRun {
+				datacenter = datacenterList(result.Datacenters)
+			}
+			result.Reason = evaluations.Explain(i, datacenter)
+			o.progress(Event{Step: StepCandidateSelected, PlanCode: result.PlanCode, Datacenter: result.Datacenter, Reason: result.Reason})
+
+			results = append(results, result)
+			ordered += co.Quantity
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, errors.Join(append([]error{fmt.Errorf("%w: %s", ErrNoCandidateAvailable, evaluations)}, errs...)...)
+	}
+
+	return results, nil
+}
+
+// splitList splits a + separated list.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, "+") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// datacenterList returns a human readable list of datacenters.
+func datacenterList(datacenters []string) string {
+	if len(datacenters) == 0 {
+		return "any datacenter"
+	}
+
+	return strings.Join(datacenters, ", ")
+}
@@ -0,0 +1,111 @@
+package orderflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCandidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expected      Candidate
+		expectedError bool
+	}{
+		{
+			name:     "plan only",
+			value:    "plan=24ska01",
+			expected: Candidate{PlanCode: "24ska01"},
+		},
+		{
+			name:  "datacenters and options",
+			value: "plan=24sk50,datacenters=rbx+gra+sbg,options=memory=ram-32g-ecc-2133-24sk50+storage=softraid-2x2000sa-24sk50",
+			expected: Candidate{
+				PlanCode:    "24sk50",
+				Datacenters: []string{"rbx", "gra", "sbg"},
+				ItemOptions: []string{"memory=ram-32g-ecc-2133-24sk50", "storage=softraid-2x2000sa-24sk50"},
+			},
+		},
+		{
+			name:          "missing plan",
+			value:         "datacenters=rbx",
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			value:         "plan=24sk50,price=10",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseCandidate(tc.value)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("ParseCandidate(%q) succeeded, want error", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCandidate(%q) failed: %v", tc.value, err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("ParseCandidate(%q) mismatch (-want +got):\n%s", tc.value, diff)
+			}
+		})
+	}
+}
+
+func TestCandidatePreferred(t *testing.T) {
+	c := Candidate{PlanCode: "24sk50", Datacenters: []string{"rbx", "gra", "sbg"}}
+
+	got := c.Preferred([]string{"sbg", "bhs", "RBX"})
+	if diff := cmp.Diff([]string{"rbx", "sbg"}, got); diff != "" {
+		t.Errorf("Preferred() mismatch (-want +got):\n%s", diff)
+	}
+
+	got = Candidate{PlanCode: "24sk50"}.Preferred([]string{"sbg", "bhs"})
+	if diff := cmp.Diff([]string{"sbg", "bhs"}, got); diff != "" {
+		t.Errorf("Preferred() without datacenters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluationsExplain(t *testing.T) {
+	evaluations := Evaluations{
+		{Rank: 1, Candidate: Candidate{PlanCode: "24sk50", Datacenters: []string{"rbx", "gra"}}},
+		{Rank: 2, Candidate: Candidate{PlanCode: "24sk60"}, Available: []string{"gra"}, Err: errors.New("checkout failed")},
+		{Rank: 3, Candidate: Candidate{PlanCode: "24ska01"}, Available: []string{"sbg", "gra"}},
+	}
+
+	testCases := []struct {
+		name       string
+		index      int
+		datacenter string
+		expected   string
+	}{
+		{
+			name:       "most preferred",
+			index:      0,
+			datacenter: "rbx",
+			expected:   "ordered 24sk50 (rank 1) in rbx: most preferred candidate",
+		},
+		{
+			name:       "fallback",
+			index:      2,
+			datacenter: "sbg",
+			expected:   "ordered 24ska01 (rank 3) in sbg: 24sk50 (rank 1) not available in rbx, gra; 24sk60 (rank 2) failed: checkout failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := evaluations.Explain(tc.index, tc.datacenter)
+			if got != tc.expected {
+				t.Errorf("Explain() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package orderflow
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoCandidateAvailable is returned when none of the candidates could be ordered.
+	ErrNoCandidateAvailable = errors.New("no candidate available")
+)
+
+// Candidate is a plan which may be ordered, with its datacenters and options.
+type Candidate struct {
+	PlanCode string
+	// Datacenters are the accepted datacenters in order of preference, all datacenters are accepted when empty.
+	Datacenters []string
+	// ItemOptions override the order item options when set, see Options.ItemOptions.
+	ItemOptions []string
+}
+
+// Candidates are candidates in order of preference.
+type Candidates []Candidate
+
+// Evaluation is the availability of a candidate at the time it was evaluated.
+type Evaluation struct {
+	// Rank is the candidate preference, starting at 1.
+	Rank      int
+	Candidate Candidate
+	// Available are the available datacenters accepted by the candidate, in order of preference.
+	Available []string
+	// Err is set when the availability could not be checked or when the order failed.
+	Err error
+}
+
+// Evaluations are evaluations in order of preference.
+type Evaluations []Evaluation
@@ -0,0 +1,135 @@
+package orderflow
+
+import (
+	"slices"
+	"strings"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+// resolveEcoOptions returns the options to configure from the user options.
+// Mandatory families not given by the user are filled with their cheapest option,
+// families given as family=any include all their options.
+func resolveEcoOptions(ecoOptions kimsufiorder.EcoItemOptions, itemOptions []string) (kimsufiorder.Options, error) {
+	if slices.Contains(itemOptions, AnyOption) {
+		// Get all mandatory options
+		return ecoOptions.GetMandatoryOptions(nil).ToOptions(), nil
+	}
+
+	userOptions, err := kimsufiorder.NewOptionsFromSlice(itemOptions)
+	if err != nil {
+		return nil, err
+	}
+	anyOptions, userOptions := userOptions.SplitByPlanCode(AnyOption)
+	anyFamilies := anyOptions.Families()
+	userFamilies := userOptions.Families()
+
+	optionFilter := func(opts kimsufiorder.EcoItemOptions, o kimsufiorder.EcoItemOption) bool {
+		defautPriceConfig := kimsufiorder.EcoItemPriceConfig{
+			Duration:    kimsufiorder.PriceDuration,
+			PricingMode: kimsufiorder.PricingMode,
+		}
+
+		// Inclue option if it is marked as any
+		if slices.Contains(anyFamilies, o.Family) {
+			return true
+		}
+
+		if !slices.Contains(userFamilies, o.Family) {
+			return true
+		}
+
+		// Include option if its family is not already included
+		current := opts.Get(o.Family)
+		if current == nil {
+			return true
+		}
+
+		newPrice := o.GetPriceByConfig(defautPriceConfig)
+		if newPrice == nil {
+			return false
+		}
+
+		currentPrice := current.GetPriceByConfig(defautPriceConfig)
+		if currentPrice == nil {
+			return false
+		}
+
+		// Include option if its price is lower than the current one
+		return newPrice.PriceInUcents < currentPrice.PriceInUcents
+	}
+
+	mandatoryOptions := ecoOptions.GetMandatoryOptions(optionFilter)
+
+	return userOptions.Merge(mandatoryOptions.ToOptions()), nil
+}
+
+// resolveVPSOptions returns the VPS options as item configurations.
+// Without user options, the first option of each mandatory family is used, preferring Linux for the OS.
+func resolveVPSOptions(vpsOptions kimsufiorder.VPSItemOptions, itemOptions []string) (kimsufiorder.ItemConfigurationRequests, error) {
+	var vpsOptionConfigs kimsufiorder.ItemConfigurationRequests
+
+	if slices.Contains(itemOptions, AnyOption) {
+		// Get all mandatory options for VPS
+		for _, option := range vpsOptions.GetMandatoryOptions(nil) {
+			vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
+				Label: option.Family,
+				Value: option.PlanCode,
+			})
+		}
+
+		return vpsOptionConfigs, nil
+	}
+
+	if len(itemOptions) > 0 {
+		userOptions, err := kimsufiorder.NewOptionsFromSlice(itemOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, option := range userOptions {
+			vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
+				Label: vpsOptionFamily(option.Family),
+				Value: option.PlanCode,
+			})
+		}
+
+		return vpsOptionConfigs, nil
+	}
+
+	// If no options specified, use the first available option for each mandatory family
+	for _, option := range vpsOptions.GetMandatoryOptions(nil) {
+		if vpsOptionConfigs.GetByLabel(option.Family) != nil {
+			continue
+		}
+
+		value := option.PlanCode
+		// For OS, prefer Linux options
+		if option.Family == "vps_os" {
+			for _, opt := range vpsOptions {
+				if opt.Family == "vps_os" && strings.Contains(strings.ToLower(opt.ProductName), "linux") {
+					value = opt.PlanCode
+					break
+				}
+			}
+		}
+
+		vpsOptionConfigs = append(vpsOptionConfigs, kimsufiorder.ItemConfigurationRequest{
+			Label: option.Family,
+			Value: value,
+		})
+	}
+
+	return vpsOptionConfigs, nil
+}
+
+// vpsOptionFamily maps user-friendly option names to API option families for VPS
+func vpsOptionFamily(userFamily string) string {
+	switch userFamily {
+	case "os":
+		return "vps_os"
+	case "backup":
+		return "vps_backupid"
+	default:
+		return userFamily
+	}
+}
@@ -0,0 +1,100 @@
+package orderflow
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+func TestResolveVPSOptions(t *testing.T) {
+	vpsOptions := kimsufiorder.VPSItemOptions{
+		{Family: "vps_os", PlanCode: "option-windows", ProductName: "Windows", Mandatory: true},
+		{Family: "vps_os", PlanCode: "option-linux", ProductName: "Linux", Mandatory: true},
+		{Family: "vps_backupid", PlanCode: "option-backup", ProductName: "Backup"},
+	}
+
+	testCases := []struct {
+		name        string
+		itemOptions []string
+		expected    kimsufiorder.ItemConfigurationRequests
+	}{
+		{
+			name:     "default prefers linux",
+			expected: kimsufiorder.ItemConfigurationRequests{{Label: "vps_os", Value: "option-linux"}},
+		},
+		{
+			name:        "user options map families",
+			itemOptions: []string{"os=option-windows", "backup=option-backup"},
+			expected: kimsufiorder.ItemConfigurationRequests{
+				{Label: "vps_os", Value: "option-windows"},
+				{Label: "vps_backupid", Value: "option-backup"},
+			},
+		},
+		{
+			name:        "any includes all mandatory options",
+			itemOptions: []string{AnyOption},
+			expected: kimsufiorder.ItemConfigurationRequests{
+				{Label: "vps_os", Value: "option-windows"},
+				{Label: "vps_os", Value: "option-linux"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveVPSOptions(vpsOptions, tc.itemOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("resolveVPSOptions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestManualConfigurations(t *testing.T) {
+	required := []kimsufiorder.ItemConfiguration{
+		{Label: kimsufiorder.ConfigurationLabelDatacenter, Required: true},
+		{Label: "region", Required: true, AllowedValues: []string{"europe"}},
+	}
+	merged := kimsufiorder.ItemConfigurationRequests{{Label: "region", Value: "europe"}}
+
+	// The datacenter is left to the checkout.
+	got, err := Options{}.manualConfigurations(merged, required)
+	if err != nil {
+		t.Fatalf("manualConfigurations() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("manualConfigurations() = %v, want none", got)
+	}
+
+	// Without Configure, other missing configurations fail.
+	_, err = Options{}.manualConfigurations(nil, required)
+	if err == nil {
+		t.Error("manualConfigurations() with missing region succeeded, want error")
+	}
+
+	var asked []string
+	o := Options{
+		Configure: func(missing []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
+			for _, c := range missing {
+				asked = append(asked, c.Label)
+			}
+			return nil, nil
+		},
+	}
+	_, err = o.manualConfigurations(merged, required)
+	if err != nil {
+		t.Fatalf("manualConfigurations() failed: %v", err)
+	}
+	_, err = o.manualConfigurations(nil, required)
+	if err != nil {
+		t.Fatalf("manualConfigurations() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"region"}, asked); diff != "" {
+		t.Errorf("Configure() called with unexpected configurations (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,190 @@
+package orderflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+	kimsufiregion "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/region"
+)
+
+const (
+	// cartLifetime is the time before a created cart expires.
+	cartLifetime = 24 * time.Hour
+)
+
+// Order runs the order workflow: it prepares a cart, see Prepare,
+// then tries to checkout the cart in each datacenter until one succeeds.
+// On dry-run, it stops before assigning the cart and returns a Result without Checkout.
+func Order(k *kimsufi.Service, o Options) (*Result, error) {
+	p, err := Prepare(k, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Checkout(nil)
+}
+
+// Inspect creates a cart and adds the item, without configuring it,
+// to list the available options, prices and required configurations.
+func Inspect(k *kimsufi.Service, o Options) (*Inspection, error) {
+	i := &Inspection{
+		PlanCode:  o.PlanCode,
+		IsVPSPlan: kimsufi.IsVPSPlanCode(o.PlanCode),
+	}
+
+	cart, err := k.CreateCart(o.Subsidiary, time.Now().Add(cartLifetime))
+	if err != nil {
+		return nil, err
+	}
+	i.CartID = cart.CartID
+	o.progress(Event{Step: StepCartCreated, CartID: cart.CartID})
+
+	if i.IsVPSPlan {
+		i.VPSOptions, err = k.GetVPSOptions(cart.CartID, o.PlanCode)
+		if err != nil {
+			return nil, err
+		}
+
+		i.VPSInfos, err = k.GetVPSInfo(cart.CartID, o.PlanCode)
+		if err != nil {
+			return nil, err
+		}
+
+		i.VPSPriceConfig = i.VPSInfos.GetPriceConfigOrDefault(o.PlanCode, o.vpsPriceConfig())
+
+		item, err := k.AddVPSItem(cart.CartID, o.PlanCode, o.Quantity, i.VPSPriceConfig)
+		if err != nil {
+			return nil, err
+		}
+		i.ItemID = int(item.ItemID)
+	} else {
+		i.EcoOptions, err = k.GetEcoOptions(cart.CartID, o.PlanCode)
+		if err != nil {
+			return nil, err
+		}
+
+		i.EcoInfos, err = k.GetEcoInfo(cart.CartID, o.PlanCode)
+		if err != nil {
+			return nil, err
+		}
+
+		i.EcoPriceConfig = i.EcoInfos.GetPriceConfigOrDefault(o.PlanCode, o.ecoPriceConfig())
+
+		item, err := k.AddEcoItem(cart.CartID, o.PlanCode, o.Quantity, i.EcoPriceConfig)
+		if err != nil {
+			return nil, err
+		}
+		i.ItemID = item.ItemID
+	}
+	o.progress(Event{Step: StepItemAdded, CartID: cart.CartID, ItemID: i.ItemID})
+
+	i.RequiredConfigurations, err = k.GetItemRequiredConfiguration(cart.CartID, i.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Completed returns true when the order was submitted.
+func (r Result) Completed() bool {
+	return r.Checkout != nil
+}
+
+// resolveDatacenters returns the datacenters of the options, AnyDatacenter is resolved to all the datacenters of an Eco plan
+// or to the datacenters in which a VPS plan is available.
+func resolveDatacenters(k *kimsufi.Service, o Options) ([]string, error) {
+	if !slices.Contains(o.Datacenters, AnyDatacenter) {
+		return o.Datacenters, nil
+	}
+
+	if kimsufi.IsVPSPlanCode(o.PlanCode) {
+		vpsAvailabilities, err := k.GetVPSAvailabilities(o.PlanCode, o.Subsidiary, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VPS availability: %w", err)
+		}
+
+		return vpsAvailabilities.GetAvailableDatacenterCodes(), nil
+	}
+
+	catalog, err := k.ListServers(o.Subsidiary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	plan := catalog.GetPlan(o.PlanCode)
+	if plan == nil {
+		return nil, fmt.Errorf("plan %s not found", o.PlanCode)
+	}
+
+	datacenterConfiguration := plan.GetConfiguration(kimsufiorder.ConfigurationLabelDatacenter)
+	if datacenterConfiguration == nil {
+		return nil, fmt.Errorf("datacenter configuration not found")
+	}
+
+	return datacenterConfiguration.Values, nil
+}
+
+// userConfigurations returns the automatic configurations merged with the user configurations and the endpoint region.
+func (o Options) userConfigurations(k *kimsufi.Service, requiredConfigurations []kimsufiorder.ItemConfiguration) kimsufiorder.ItemConfigurationRequests {
+	itemConfigurations := kimsufiorder.NewItemConfigurationsFromMap(o.ItemConfigurations)
+	r := kimsufiregion.GetRegionFromEndpoint(o.Endpoint)
+	if r != nil {
+		itemConfigurations.Add(kimsufiorder.ConfigurationLabelRegion, r.Region)
+	}
+
+	autoConfigs := k.GenerateItemAutoConfigurations(requiredConfigurations)
+
+	return autoConfigs.Merge(itemConfigurations)
+}
+
+// manualConfigurations returns a value for each required configuration not already set in mergedConfigs, using Configure.
+// The datacenter configuration is skipped since it is set at checkout.
+func (o Options) manualConfigurations(mergedConfigs kimsufiorder.ItemConfigurationRequests, requiredConfigs []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
+	var missing []kimsufiorder.ItemConfiguration
+	for _, config := range requiredConfigs {
+		if !config.Required || mergedConfigs.GetByLabel(config.Label) != nil {
+			continue
+		}
+		if config.Label == kimsufiorder.ConfigurationLabelDatacenter {
+			continue
+		}
+		missing = append(missing, config)
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	if o.Configure == nil {
+		config := missing[0]
+		return nil, fmt.Errorf("missing item configuration %s (allowed values: %s)", config.Label, strings.Join(config.AllowedValues, ", "))
+	}
+
+	return o.Configure(missing)
+}
+
+// progress reports the event to the Progress callback, if any.
+func (o Options) progress(e Event) {
+	if o.Progress != nil {
+		o.Progress(e)
+	}
+}
+
+func (o Options) ecoPriceConfig() kimsufiorder.EcoItemPriceConfig {
+	return kimsufiorder.EcoItemPriceConfig{
+		Duration:    o.PriceDuration,
+		PricingMode: o.PriceMode,
+	}
+}
+
+func (o Options) vpsPriceConfig() kimsufiorder.VPSItemPriceConfig {
+	return kimsufiorder.VPSItemPriceConfig{
+		Duration:    o.PriceDuration,
+		PricingMode: o.PriceMode,
+	}
+}
@@ -0,0 +1,209 @@
+package orderflow
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+const (
+	// AnyDatacenter in Options.Datacenters tries all datacenters of the plan.
+	AnyDatacenter = "any"
+	// AnyOption in Options.ItemOptions includes all mandatory options, or all options of a family with family=any.
+	AnyOption = "any"
+)
+
+const (
+	StepCartCreated           Step = "cart-created"
+	StepItemAdded             Step = "item-added"
+	StepItemConfigured        Step = "item-configured"
+	StepDatacentersResolved   Step = "datacenters-resolved"
+	StepReady                 Step = "ready"
+	StepDryRun                Step = "dry-run"
+	StepCartAssigned          Step = "cart-assigned"
+	StepCartRefreshed         Step = "cart-refreshed"
+	StepOptionConfigured      Step = "option-configured"
+	StepDatacenterConfigured  Step = "datacenter-configured"
+	StepBudgetChecked         Step = "budget-checked"
+	StepDatacenterUnavailable Step = "datacenter-unavailable"
+	StepCheckoutFailed        Step = "checkout-failed"
+	StepOrderCompleted        Step = "order-completed"
+	StepCandidateEvaluated    Step = "candidate-evaluated"
+	StepCandidateSelected     Step = "candidate-selected"
+)
+
+var (
+	// ErrNoDatacenterAvailable is returned when the checkout failed in every datacenter.
+	ErrNoDatacenterAvailable = errors.New("no datacenter available")
+	// ErrMissingCredentials is returned when an order is submitted without credentials.
+	ErrMissingCredentials = errors.New("OVH API credentials are required to submit an order")
+)
+
+// Options holds the parameters of an order.
+type Options struct {
+	// Endpoint is the OVH API endpoint, used to find the item region.
+	Endpoint string
+	// Subsidiary is the OVH subsidiary the cart is created for.
+	Subsidiary string
+	PlanCode   string
+	// Datacenters are tried in order until one checkout succeeds, AnyDatacenter tries all datacenters of the plan.
+	Datacenters []string
+	Quantity    int
+
+	// ItemConfigurations are the item configurations by label (e.g. region=europe).
+	ItemConfigurations map[string]string
+	// ItemOptions are the item options as family=planCode (e.g. memory=ram-64g-noecc-2133-24ska01).
+	ItemOptions []string
+
+	PriceDuration string
+	PriceMode     string
+
+	AutoPay bool
+	// DryRun stops before assigning the cart and submitting the order.
+	DryRun bool
+
+	// Credentials are required unless DryRun is set.
+	Credentials *Credentials
+
+	// Budget, when set, is checked against the checkout preview before the checkout.
+	Budget *Budget
+
+	// Configure returns the values of the required item configurations which are not set,
+	// e.g. by asking the user. When nil, missing configurations are an error.
+	Configure ConfigureFunc
+	// Progress is called at each step of the order, when not nil.
+	Progress ProgressFunc
+}
+
+// Credentials are the OVH API credentials used to submit an order.
+type Credentials struct {
+	AppKey      string
+	AppSecret   string
+	ConsumerKey string
+}
+
+// ConfigureFunc returns a value for each of the missing required configurations.
+type ConfigureFunc func(missing []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error)
+
+// ProgressFunc receives the order progress.
+type ProgressFunc func(Event)
+
+// Step identifies a step of the order workflow.
+type Step string
+
+// Event reports the progress of an order.
+// Only the fields related to the step are set.
+type Event struct {
+	Step     Step
+	PlanCode string
+	CartID   string
+	ItemID   int
+
+	// Label and Value are set for configured items and options.
+	Label string
+	Value string
+
+	Datacenter  string
+	Datacenters []string
+	Options     []string
+	// Combinations is the number of option and datacenter combinations which may be tried.
+	Combinations int
+
+	// Expire is set when the cart expiry is refreshed.
+	Expire time.Time
+
+	URL string
+	Err error
+	// Reason explains candidate evaluations and selections.
+	Reason string
+	// Prices are set when the budget is checked.
+	Prices *Prices
+}
+
+// Result is the outcome of an order.
+type Result struct {
+	PlanCode string
+	CartID   string
+	ItemID   int
+	// Options are the plan codes of the configured item options.
+	Options []string
+	// Datacenters are the datacenters which were to be tried.
+	Datacenters []string
+	DryRun      bool
+
+	// Datacenter and Checkout are set once the order is completed.
+	Datacenter string
+	Checkout   *kimsufiorder.CheckoutResponse
+
+	// Reason explains why the plan was chosen among candidates, see OrderCandidates.
+	Reason string
+}
+
+// Prepared is a cart ready to be checked out, see Prepare.
+type Prepared struct {
+	PlanCode string
+	CartID   string
+	ItemID   int
+	// Options are the plan codes of the item options.
+	Options []string
+	// Renewal is the catalog price of each renewal of the ordered duration, without tax.
+	Renewal float64
+	// Datacenters are the datacenters tried on checkout when none is given.
+	Datacenters []string
+	// Expire is the time at which the cart expires, see Refresh.
+	Expire time.Time
+	// Assigned is true once the cart is assigned to the account, it is never set on dry-run.
+	Assigned bool
+
+	k *kimsufi.Service
+	o Options
+	// datacenterLabel is the label of the datacenter item configuration set on checkout,
+	// it is empty when the datacenter is configured on prepare.
+	datacenterLabel string
+	// combinations are the Eco options combinations, the first one is configured on prepare.
+	combinations []kimsufiorder.Options
+	// configured is the index of the options combination configured on the item.
+	configured  int
+	priceConfig kimsufiorder.EcoItemPriceConfig
+	// priceDuration is the ordered duration of the item (e.g. P1M), used to compute the monthly price.
+	priceDuration string
+	done          bool
+}
+
+// Preview is what a cart checkout would order, see Preview.
+type Preview struct {
+	PlanCode   string
+	CartID     string
+	Datacenter string
+	// Options are the plan codes of the item options.
+	Options []string
+	// Checkout is the checkout preview, it has no order ID nor URL.
+	Checkout *kimsufiorder.CheckoutResponse
+	Prices   Prices
+	// PriceDuration is the ordered duration of the item (e.g. P1M), it may differ from Options.PriceDuration when that was not available for the plan.
+	PriceDuration string
+	// Renewal is the catalog price of each renewal of the ordered duration, without tax.
+	Renewal float64
+}
+
+// Inspection lists the values available to configure an order, see Inspect.
+// Eco or VPS fields are set depending on the plan.
+type Inspection struct {
+	PlanCode  string
+	IsVPSPlan bool
+	CartID    string
+	ItemID    int
+
+	EcoOptions     kimsufiorder.EcoItemOptions
+	EcoInfos       kimsufiorder.EcoItemInfos
+	EcoPriceConfig kimsufiorder.EcoItemPriceConfig
+
+	VPSOptions     kimsufiorder.VPSItemOptions
+	VPSInfos       kimsufiorder.VPSItemInfos
+	VPSPriceConfig kimsufiorder.VPSItemPriceConfig
+
+	// RequiredConfigurations are the item configurations of the added item.
+	RequiredConfigurations []kimsufiorder.ItemConfiguration
+}
@@ -0,0 +1,201 @@
+package orderflow
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+// OrderParallel prepares one cart per datacenter concurrently and races their checkouts.
+// At most maxOrders orders are placed: a checkout is only submitted while the orders placed
+// and the checkouts in flight are fewer than maxOrders.
+// Only the first Eco options combination is tried. Progress and Configure are never called concurrently,
+// and Configure is only asked once for each configuration.
+// It returns the completed orders, or the dry-run results on dry-run.
+func OrderParallel(k *kimsufi.Service, o Options, maxOrders int) ([]*Result, error) {
+	if maxOrders <= 0 {
+		return nil, fmt.Errorf("maximum number of orders must be positive")
+	}
+	if o.PlanCode == "" {
+		return nil, fmt.Errorf("plan code is required")
+	}
+	if o.Subsidiary == "" {
+		return nil, fmt.Errorf("subsidiary is required")
+	}
+	if !o.DryRun && o.Credentials == nil {
+		return nil, ErrMissingCredentials
+	}
+
+	datacenters, err := resolveDatacenters(k, o)
+	if err != nil {
+		return nil, err
+	}
+	if len(datacenters) == 0 {
+		return nil, fmt.Errorf("%w for plan %s", ErrNoDatacenterAvailable, o.PlanCode)
+	}
+
+	if o.Progress != nil {
+		var mu sync.Mutex
+		progress := o.Progress
+		o.Progress = func(e Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress(e)
+		}
+	}
+
+	if o.Configure != nil {
+		o.Configure = onceConfigure(o.Configure)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []*Result
+		errs    []error
+	)
+	limiter := newOrderLimiter(maxOrders)
+	for _, datacenter := range datacenters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result, err := raceCheckout(k, o, datacenter, limiter)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("datacenter %s: %w", datacenter, err))
+			}
+			if result != nil {
+				results = append(results, result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(results) == 0 {
+		return nil, errors.Join(append([]error{fmt.Errorf("%w for plan %s", ErrNoDatacenterAvailable, o.PlanCode)}, errs...)...)
+	}
+
+	return results, nil
+}
+
+// raceCheckout prepares a cart configured with the datacenter and checks it out once the limiter allows it.
+// It returns nil when the datacenter is not available or when enough orders were placed.
+func raceCheckout(k *kimsufi.Service, o Options, datacenter string, limiter *orderLimiter) (*Result, error) {
+	o.Datacenters = []string{datacenter}
+	p, err := Prepare(k, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.Assigned {
+		return p.Checkout(nil)
+	}
+
+	resp, err := p.configureDatacenter(datacenter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !limiter.acquire() {
+		return nil, nil
+	}
+
+	result := &Result{
+		PlanCode:    p.PlanCode,
+		CartID:      p.CartID,
+		ItemID:      p.ItemID,
+		Options:     p.Options,
+		Datacenters: o.Datacenters,
+	}
+	completed, err := p.submit(datacenter, resp, result)
+	limiter.release(completed)
+	if err != nil || !completed {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// onceConfigure returns a ConfigureFunc safe for concurrent use which asks configure only once for each configuration.
+func onceConfigure(configure ConfigureFunc) ConfigureFunc {
+	var mu sync.Mutex
+	var answered kimsufiorder.ItemConfigurationRequests
+
+	return func(missing []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var ask []kimsufiorder.ItemConfiguration
+		for _, config := range missing {
+			if answered.GetByLabel(config.Label) == nil {
+				ask = append(ask, config)
+			}
+		}
+
+		if len(ask) > 0 {
+			answers, err := configure(ask)
+			if err != nil {
+				return nil, err
+			}
+			answered = answered.Merge(answers)
+		}
+
+		var configurations kimsufiorder.ItemConfigurationRequests
+		for _, config := range missing {
+			if c := answered.GetByLabel(config.Label); c != nil {
+				configurations = append(configurations, *c)
+			}
+		}
+
+		return configurations, nil
+	}
+}
+
+// orderLimiter bounds the orders placed plus the checkouts in flight.
+type orderLimiter struct {
+	cond     *sync.Cond
+	max      int
+	inFlight int
+	placed   int
+}
+
+func newOrderLimiter(max int) *orderLimiter {
+	return &orderLimiter{
+		cond: sync.NewCond(&sync.Mutex{}),
+		max:  max,
+	}
+}
+
+// acquire waits until a checkout can be submitted, it returns false once the maximum number of orders is placed.
+func (l *orderLimiter) acquire() bool {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	for l.placed < l.max && l.placed+l.inFlight >= l.max {
+		l.cond.Wait()
+	}
+	if l.placed >= l.max {
+		return false
+	}
+	l.inFlight++
+
+	return true
+}
+
+// release ends a checkout, placed tells whether it placed an order.
+func (l *orderLimiter) release(placed bool) {
+	l.cond.L.Lock()
+	defer l.cond.L.Unlock()
+
+	l.inFlight--
+	if placed {
+		l.placed++
+	}
+	l.cond.Broadcast()
+}
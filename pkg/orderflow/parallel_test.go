@@ -0,0 +1,119 @@
+package orderflow
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+func TestOrderLimiter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		max       int
+		attempts  int
+		available func(i int) bool
+		expected  int
+	}{
+		{
+			name:      "all available",
+			max:       2,
+			attempts:  10,
+			available: func(int) bool { return true },
+			expected:  2,
+		},
+		{
+			name:      "few available",
+			max:       3,
+			attempts:  10,
+			available: func(i int) bool { return i%5 == 0 },
+			expected:  2,
+		},
+		{
+			name:      "none available",
+			max:       1,
+			attempts:  5,
+			available: func(int) bool { return false },
+			expected:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newOrderLimiter(tc.max)
+
+			var wg sync.WaitGroup
+			var placed, inFlight, maxInFlight atomic.Int32
+			for i := 0; i < tc.attempts; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if !l.acquire() {
+						return
+					}
+
+					n := inFlight.Add(1) + placed.Load()
+					for {
+						m := maxInFlight.Load()
+						if n <= m || maxInFlight.CompareAndSwap(m, n) {
+							break
+						}
+					}
+
+					ok := tc.available(i)
+					if ok {
+						placed.Add(1)
+					}
+					inFlight.Add(-1)
+					l.release(ok)
+				}()
+			}
+			wg.Wait()
+
+			if got := int(placed.Load()); got != tc.expected {
+				t.Errorf("placed %d orders, want %d", got, tc.expected)
+			}
+			if got := int(maxInFlight.Load()); got > tc.max {
+				t.Errorf("%d orders placed or in flight at once, want at most %d", got, tc.max)
+			}
+		})
+	}
+}
+
+func TestOnceConfigure(t *testing.T) {
+	var asked []string
+	configure := onceConfigure(func(missing []kimsufiorder.ItemConfiguration) (kimsufiorder.ItemConfigurationRequests, error) {
+		var answers kimsufiorder.ItemConfigurationRequests
+		for _, c := range missing {
+			asked = append(asked, c.Label)
+			answers = append(answers, kimsufiorder.ItemConfigurationRequest{Label: c.Label, Value: c.AllowedValues[0]})
+		}
+		return answers, nil
+	})
+
+	region := kimsufiorder.ItemConfiguration{Label: "region", AllowedValues: []string{"europe"}}
+	os := kimsufiorder.ItemConfiguration{Label: "os", AllowedValues: []string{"linux"}}
+
+	for _, missing := range [][]kimsufiorder.ItemConfiguration{{region}, {region, os}} {
+		_, err := configure(missing)
+		if err != nil {
+			t.Fatalf("configure() failed: %v", err)
+		}
+	}
+
+	got, err := configure([]kimsufiorder.ItemConfiguration{os, region})
+	if err != nil {
+		t.Fatalf("configure() failed: %v", err)
+	}
+
+	expected := kimsufiorder.ItemConfigurationRequests{{Label: "os", Value: "linux"}, {Label: "region", Value: "europe"}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("configure() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"region", "os"}, asked); diff != "" {
+		t.Errorf("configure asked unexpected configurations (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,528 @@
+package orderflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+const (
+	// cartRefreshMargin is the time before the cart expiry from which a prepared cart needs a refresh.
+	cartRefreshMargin = 1 * time.Hour
+)
+
+// Prepare creates a cart, adds and configures the item, sets its options and assigns the cart,
+// leaving only the datacenter configuration and the checkout to Prepared.Checkout.
+// On dry-run, the cart is not assigned.
+func Prepare(k *kimsufi.Service, o Options) (*Prepared, error) {
+	if o.PlanCode == "" {
+		return nil, fmt.Errorf("plan code is required")
+	}
+	if o.Subsidiary == "" {
+		return nil, fmt.Errorf("subsidiary is required")
+	}
+	if !o.DryRun && o.Credentials == nil {
+		return nil, ErrMissingCredentials
+	}
+	if len(o.Datacenters) == 0 {
+		return nil, fmt.Errorf("datacenter is required")
+	}
+
+	if kimsufi.IsVPSPlanCode(o.PlanCode) {
+		return prepareVPS(k, o)
+	}
+
+	return prepareEco(k, o)
+}
+
+// prepareEco prepares an Eco plan cart
+func prepareEco(k *kimsufi.Service, o Options) (*Prepared, error) {
+	planCode := o.PlanCode
+	datacenters := o.Datacenters
+
+	// Create cart
+	expire := time.Now().Add(cartLifetime)
+	cart, err := k.CreateCart(o.Subsidiary, expire)
+	if err != nil {
+		return nil, err
+	}
+	o.progress(Event{Step: StepCartCreated, CartID: cart.CartID})
+
+	// Retrieve item options
+	ecoOptions, err := k.GetEcoOptions(cart.CartID, planCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve item informations
+	ecoInfo, err := k.GetEcoInfo(cart.CartID, planCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure price config is valid, otherwise use default
+	priceConfig := ecoInfo.GetPriceConfigOrDefault(planCode, o.ecoPriceConfig())
+
+	// Add plan to cart
+	item, err := k.AddEcoItem(cart.CartID, planCode, o.Quantity, priceConfig)
+	if err != nil {
+		return nil, err
+	}
+	o.progress(Event{Step: StepItemAdded, CartID: cart.CartID, ItemID: item.ItemID})
+
+	requiredConfigurations, err := k.GetItemRequiredConfiguration(cart.CartID, item.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(datacenters, AnyDatacenter) {
+		datacenters, err = resolveDatacenters(k, o)
+		if err != nil {
+			return nil, err
+		}
+		o.progress(Event{Step: StepDatacentersResolved, CartID: cart.CartID, Datacenters: datacenters})
+	}
+
+	// Prepare item configurations
+	userConfigs := o.userConfigurations(k, requiredConfigurations)
+	manualConfigs, err := o.manualConfigurations(userConfigs, requiredConfigurations)
+	if err != nil {
+		return nil, err
+	}
+	configurations := userConfigs.Merge(manualConfigs)
+
+	// Configure item
+	for _, configuration := range configurations {
+		resp, err := k.AddItemConfiguration(cart.CartID, item.ItemID, configuration)
+		if err != nil {
+			return nil, err
+		}
+		o.progress(Event{Step: StepItemConfigured, CartID: cart.CartID, ItemID: item.ItemID, Label: resp.Label, Value: resp.Value})
+	}
+
+	// Prepare item options
+	mergedOptions, err := resolveEcoOptions(ecoOptions, o.ItemOptions)
+	if err != nil {
+		return nil, err
+	}
+	optionsCombinations := kimsufiorder.NewOptionsCombinationsFromSlice(mergedOptions)
+
+	var firstOptions kimsufiorder.Options
+	if len(optionsCombinations) > 0 {
+		firstOptions = optionsCombinations[0]
+	}
+
+	p := &Prepared{
+		PlanCode:        planCode,
+		CartID:          cart.CartID,
+		ItemID:          item.ItemID,
+		Options:         mergedOptions.PlanCodes(),
+		Renewal:         ecoRenewal(ecoInfo, ecoOptions, planCode, firstOptions, priceConfig) * float64(max(o.Quantity, 1)),
+		Datacenters:     datacenters,
+		Expire:          expire,
+		k:               k,
+		o:               o,
+		datacenterLabel: kimsufiorder.ConfigurationLabelDatacenter,
+		combinations:    optionsCombinations,
+		priceConfig:     priceConfig,
+		priceDuration:   priceConfig.Duration,
+	}
+	o.progress(Event{Step: StepReady, CartID: cart.CartID, ItemID: item.ItemID, Options: p.Options, Datacenters: datacenters, Combinations: len(optionsCombinations) * len(datacenters)})
+
+	// Stop on dry-run
+	if o.DryRun {
+		return p, nil
+	}
+
+	err = p.assign()
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure the first options combination, the others are tried on checkout
+	if len(optionsCombinations) > 0 {
+		err = p.configureOptions(0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// prepareVPS prepares a VPS plan cart
+func prepareVPS(k *kimsufi.Service, o Options) (*Prepared, error) {
+	planCode := o.PlanCode
+	datacenters := o.Datacenters
+
+	// Create cart
+	expire := time.Now().Add(cartLifetime)
+	cart, err := k.CreateCart(o.Subsidiary, expire)
+	if err != nil {
+		return nil, err
+	}
+	o.progress(Event{Step: StepCartCreated, CartID: cart.CartID})
+
+	// Retrieve VPS item options
+	vpsOptions, err := k.GetVPSOptions(cart.CartID, planCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve VPS item information
+	vpsInfo, err := k.GetVPSInfo(cart.CartID, planCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure price config is valid, otherwise use default
+	priceConfig := vpsInfo.GetPriceConfigOrDefault(planCode, o.vpsPriceConfig())
+
+	// Add VPS plan to cart
+	vpsItem, err := k.AddVPSItem(cart.CartID, planCode, o.Quantity, priceConfig)
+	if err != nil {
+		return nil, err
+	}
+	itemID := int(vpsItem.ItemID)
+	o.progress(Event{Step: StepItemAdded, CartID: cart.CartID, ItemID: itemID})
+
+	requiredConfigurations, err := k.GetItemRequiredConfiguration(cart.CartID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// For VPS, validate datacenters against availability API
+	anyDatacenter := slices.Contains(datacenters, AnyDatacenter)
+	if anyDatacenter {
+		// None may be available yet when preparing ahead of a restock
+		datacenters, err = resolveDatacenters(k, o)
+		if err != nil {
+			return nil, err
+		}
+		o.progress(Event{Step: StepDatacentersResolved, CartID: cart.CartID, Datacenters: datacenters})
+	}
+
+	// Prepare item configurations
+	userConfigs := o.userConfigurations(k, requiredConfigurations)
+
+	// A single datacenter is configured with the item, others are tried on checkout
+	datacenterLabel := kimsufiorder.ConfigurationLabelDatacenter
+	if !anyDatacenter && len(datacenters) == 1 {
+		userConfigs = userConfigs.Merge(kimsufiorder.ItemConfigurationRequests{{
+			Label: kimsufiorder.ConfigurationLabelDatacenter,
+			Value: datacenters[0],
+		}})
+	}
+	if userConfigs.GetByLabel(kimsufiorder.ConfigurationLabelDatacenter) != nil {
+		datacenterLabel = ""
+	}
+
+	// Handle VPS options - convert them to item configurations
+	vpsOptionConfigs, err := resolveVPSOptions(vpsOptions, o.ItemOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge VPS option configurations with user configurations
+	userConfigs = userConfigs.Merge(vpsOptionConfigs)
+
+	// For VPS, skip manual configuration if datacenter is already specified
+	configurations := userConfigs
+	if datacenterLabel != "" {
+		manualConfigs, err := o.manualConfigurations(userConfigs, requiredConfigurations)
+		if err != nil {
+			return nil, err
+		}
+		configurations = userConfigs.Merge(manualConfigs)
+	}
+
+	// Configure item
+	for _, configuration := range configurations {
+		resp, err := k.AddItemConfiguration(cart.CartID, itemID, configuration)
+		if err != nil {
+			return nil, err
+		}
+		o.progress(Event{Step: StepItemConfigured, CartID: cart.CartID, ItemID: itemID, Label: resp.Label, Value: resp.Value})
+	}
+
+	var options []string
+	for _, c := range vpsOptionConfigs {
+		options = append(options, c.Value)
+	}
+
+	p := &Prepared{
+		PlanCode:        planCode,
+		CartID:          cart.CartID,
+		ItemID:          itemID,
+		Options:         options,
+		Renewal:         vpsRenewal(vpsInfo, vpsOptions, planCode, options, priceConfig) * float64(max(o.Quantity, 1)),
+		Datacenters:     datacenters,
+		Expire:          expire,
+		k:               k,
+		o:               o,
+		datacenterLabel: datacenterLabel,
+		priceDuration:   priceConfig.Duration,
+	}
+	o.progress(Event{Step: StepReady, CartID: cart.CartID, ItemID: itemID, Options: options, Datacenters: datacenters, Combinations: len(datacenters)})
+
+	// Stop on dry-run
+	if o.DryRun {
+		return p, nil
+	}
+
+	err = p.assign()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ecoRenewal returns the renewal price of the plan with its options, for one item.
+func ecoRenewal(ecoInfo kimsufiorder.EcoItemInfos, ecoOptions kimsufiorder.EcoItemOptions, planCode string, options kimsufiorder.Options, priceConfig kimsufiorder.EcoItemPriceConfig) float64 {
+	var renewal float64
+
+	if info := ecoInfo.GetByPlanCode(planCode); info != nil {
+		if price := info.GetRenewPrice(priceConfig); price != nil {
+			renewal += price.Price.Value
+		}
+	}
+
+	for _, option := range options {
+		for _, ecoOption := range ecoOptions {
+			if ecoOption.PlanCode != option.PlanCode {
+				continue
+			}
+			if price := ecoOption.GetRenewPriceByConfig(priceConfig); price != nil {
+				renewal += price.Price.Value
+			}
+			break
+		}
+	}
+
+	return renewal
+}
+
+// vpsRenewal returns the renewal price of the VPS plan with its options, for one item.
+func vpsRenewal(vpsInfo kimsufiorder.VPSItemInfos, vpsOptions kimsufiorder.VPSItemOptions, planCode string, options []string, priceConfig kimsufiorder.VPSItemPriceConfig) float64 {
+	var renewal float64
+
+	if info := vpsInfo.GetByPlanCode(planCode); info != nil {
+		if price := info.GetRenewPrice(priceConfig); price != nil {
+			renewal += price.Price.Value
+		}
+	}
+
+	for _, option := range vpsOptions {
+		if !slices.Contains(options, option.PlanCode) {
+			continue
+		}
+		if price := option.GetPriceByConfig(priceConfig); price != nil {
+			renewal += price.Price.Value
+		}
+	}
+
+	return renewal
+}
+
+// Checkout configures the datacenter and checks out the cart, trying each of the datacenters until one succeeds.
+// Without datacenters, the prepared datacenters are tried. Eco options combinations are tried in turn when none of the datacenters succeeds.
+// On dry-run, it returns a Result without Checkout.
+func (p *Prepared) Checkout(datacenters []string) (*Result, error) {
+	if p.done {
+		return nil, fmt.Errorf("cart %s can no longer be checked out", p.CartID)
+	}
+
+	if len(datacenters) == 0 {
+		datacenters = p.Datacenters
+	}
+	if p.datacenterLabel == "" {
+		// The datacenter is already configured, it is the only one which can be checked out
+		datacenters = slices.DeleteFunc(slices.Clone(datacenters), func(dc string) bool {
+			return len(p.Datacenters) == 0 || !strings.EqualFold(dc, p.Datacenters[0])
+		})
+	}
+
+	result := &Result{
+		PlanCode:    p.PlanCode,
+		CartID:      p.CartID,
+		ItemID:      p.ItemID,
+		Options:     p.Options,
+		Datacenters: datacenters,
+		DryRun:      !p.Assigned,
+	}
+
+	// Stop on dry-run
+	if !p.Assigned {
+		p.o.progress(Event{Step: StepDryRun, CartID: p.CartID, ItemID: p.ItemID})
+		return result, nil
+	}
+
+	// Try the preferred options first, then the other combinations,
+	// the preferred options are configured again when a previous call left other ones
+	for i := 0; i < max(len(p.combinations), 1); i++ {
+		if i != p.configured {
+			err := p.configureOptions(i)
+			if err != nil {
+				p.done = true
+				return nil, err
+			}
+		}
+
+		// Try all datacenters
+		for _, datacenter := range datacenters {
+			completed, err := p.checkout(datacenter, result)
+			if err != nil || completed {
+				p.done = true
+				return result, err
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w for plan %s", ErrNoDatacenterAvailable, p.PlanCode)
+}
+
+// Done returns true once the cart is checked out, or left in an unknown state by a failed checkout.
+// A new cart must then be prepared.
+func (p *Prepared) Done() bool {
+	return p.done
+}
+
+// NeedsRefresh returns true when the cart expires soon, see Refresh.
+func (p *Prepared) NeedsRefresh(now time.Time) bool {
+	return p.Expire.Sub(now) < cartRefreshMargin
+}
+
+// Refresh postpones the cart expiry.
+func (p *Prepared) Refresh(now time.Time) error {
+	expire := now.Add(cartLifetime)
+
+	err := p.k.UpdateCartExpire(p.CartID, expire, p.Assigned)
+	if err != nil {
+		return err
+	}
+	p.Expire = expire
+	p.o.progress(Event{Step: StepCartRefreshed, CartID: p.CartID, Expire: expire})
+
+	return nil
+}
+
+// assign authenticates the service and assigns the cart to the user account.
+func (p *Prepared) assign() error {
+	k, err := p.k.WithAuth(p.o.Credentials.AppKey, p.o.Credentials.AppSecret, p.o.Credentials.ConsumerKey)
+	if err != nil {
+		return err
+	}
+
+	// Assign cart to user account
+	err = k.AssignCart(p.CartID)
+	if err != nil {
+		return err
+	}
+	p.k = k
+	p.Assigned = true
+	p.o.progress(Event{Step: StepCartAssigned, CartID: p.CartID})
+
+	return nil
+}
+
+// configureOptions configures the item options of the i-th options combination.
+func (p *Prepared) configureOptions(i int) error {
+	for _, option := range p.combinations[i] {
+		err := p.k.ConfigureEcoItemOption(p.CartID, p.ItemID, option, p.priceConfig)
+		if err != nil {
+			return err
+		}
+		p.o.progress(Event{Step: StepOptionConfigured, CartID: p.CartID, ItemID: p.ItemID, Label: option.Family, Value: option.PlanCode})
+	}
+	p.configured = i
+
+	return nil
+}
+
+// checkout configures the datacenter, unless it is already configured, and checks out the cart.
+// It returns true when the order is completed, the datacenter configuration is removed otherwise.
+func (p *Prepared) checkout(datacenter string, result *Result) (bool, error) {
+	resp, err := p.configureDatacenter(datacenter)
+	if err != nil {
+		return false, err
+	}
+
+	return p.submit(datacenter, resp, result)
+}
+
+// configureDatacenter configures the datacenter, unless it is already configured.
+// It returns the added configuration, nil when the datacenter was already configured.
+func (p *Prepared) configureDatacenter(datacenter string) (*kimsufiorder.ItemConfigurationResponse, error) {
+	var resp *kimsufiorder.ItemConfigurationResponse
+	if p.datacenterLabel != "" {
+		var err error
+		resp, err = p.k.AddItemConfiguration(p.CartID, p.ItemID, kimsufiorder.ItemConfigurationRequest{
+			Label: p.datacenterLabel,
+			Value: datacenter,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.o.progress(Event{Step: StepDatacenterConfigured, CartID: p.CartID, ItemID: p.ItemID, Datacenter: datacenter})
+
+	return resp, nil
+}
+
+// checkBudget checks the cart prices against the budget, from the checkout preview.
+func (p *Prepared) checkBudget(datacenter string) error {
+	preview, err := p.k.GetCheckoutPreview(p.CartID)
+	if err != nil {
+		return fmt.Errorf("failed to preview checkout: %w", err)
+	}
+
+	prices := NewPrices(preview, p.priceDuration, p.o.Quantity)
+	err = p.o.Budget.Check(prices)
+	if err != nil {
+		return err
+	}
+	p.o.progress(Event{Step: StepBudgetChecked, CartID: p.CartID, ItemID: p.ItemID, Datacenter: datacenter, Prices: &prices})
+
+	return nil
+}
+
+// submit checks out the cart configured with the datacenter, once its prices are checked against the budget.
+// It returns true when the order is completed, the datacenter configuration resp is removed otherwise.
+func (p *Prepared) submit(datacenter string, resp *kimsufiorder.ItemConfigurationResponse, result *Result) (bool, error) {
+	if p.o.Budget != nil {
+		err := p.checkBudget(datacenter)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Checkout and complete the order
+	checkoutResp, err := p.k.CheckoutCart(p.CartID, p.o.AutoPay)
+	if err == nil {
+		result.Datacenter = datacenter
+		result.Checkout = checkoutResp
+		p.o.progress(Event{Step: StepOrderCompleted, CartID: p.CartID, ItemID: p.ItemID, Datacenter: datacenter, URL: checkoutResp.URL})
+		return true, nil
+	}
+
+	if kimsufi.IsNotAvailableError(err) {
+		p.o.progress(Event{Step: StepDatacenterUnavailable, CartID: p.CartID, ItemID: p.ItemID, Datacenter: datacenter, Err: err})
+	} else {
+		p.o.progress(Event{Step: StepCheckoutFailed, CartID: p.CartID, ItemID: p.ItemID, Datacenter: datacenter, Err: err})
+	}
+
+	// Remove datacenter configuration if we added it
+	if resp != nil {
+		err = p.k.RemoveItemConfiguration(p.CartID, p.ItemID, resp.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
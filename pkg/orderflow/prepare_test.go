@@ -0,0 +1,86 @@
+package orderflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ovh/go-ovh/ovh"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+func TestPreparedCheckoutResetsOptions(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		configured []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/auth/time":
+			fmt.Fprint(w, time.Now().Unix())
+		case strings.HasSuffix(r.URL.Path, "/eco/options"):
+			var req kimsufiorder.EcoItemOptionRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				t.Errorf("invalid option request: %v", err)
+			}
+			mu.Lock()
+			configured = append(configured, req.PlanCode)
+			mu.Unlock()
+			fmt.Fprint(w, "{}")
+		case strings.HasSuffix(r.URL.Path, "/configuration") && r.Method == http.MethodPost:
+			fmt.Fprint(w, `{"id":1,"label":"dedicated_datacenter","value":"rbx"}`)
+		case strings.HasSuffix(r.URL.Path, "/checkout"):
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"Item 24ska01 is not available in rbx"}`)
+		default:
+			fmt.Fprint(w, "null")
+		}
+	}))
+	defer server.Close()
+
+	ovh.Endpoints["orderflow-test"] = server.URL
+	defer delete(ovh.Endpoints, "orderflow-test")
+
+	k, err := kimsufi.NewService("orderflow-test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k = k.WithRetryPolicy(kimsufi.RetryPolicy{MaxAttempts: 1})
+
+	p := &Prepared{
+		PlanCode:        "24ska01",
+		CartID:          "cart",
+		ItemID:          1,
+		Datacenters:     []string{"rbx"},
+		Assigned:        true,
+		k:               k,
+		datacenterLabel: kimsufiorder.ConfigurationLabelDatacenter,
+		combinations: []kimsufiorder.Options{
+			{{Family: "memory", PlanCode: "ram-32g"}},
+			{{Family: "memory", PlanCode: "ram-64g"}},
+		},
+	}
+
+	for range 2 {
+		_, err := p.Checkout(nil)
+		if !errors.Is(err, ErrNoDatacenterAvailable) {
+			t.Fatalf("Checkout() = %v, want %v", err, ErrNoDatacenterAvailable)
+		}
+	}
+
+	// The preferred options are configured again before the second pass
+	expected := []string{"ram-64g", "ram-32g", "ram-64g"}
+	if diff := cmp.Diff(expected, configured); diff != "" {
+		t.Errorf("configured options mismatch (-want +got):\n%s", diff)
+	}
+}
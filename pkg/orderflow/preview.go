@@ -0,0 +1,59 @@
+package orderflow
+
+import (
+	"fmt"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+)
+
+// PreviewOrder prepares and assigns a cart, then returns its checkout preview in the first datacenter without placing the order.
+// The checkout preview is only available for assigned carts, credentials are required.
+func PreviewOrder(k *kimsufi.Service, o Options) (*Preview, error) {
+	o.DryRun = false
+	p, err := Prepare(k, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Datacenters) == 0 {
+		return nil, fmt.Errorf("%w for plan %s", ErrNoDatacenterAvailable, p.PlanCode)
+	}
+
+	return p.Preview(p.Datacenters[0])
+}
+
+// Preview configures the datacenter and returns the checkout preview of the cart, the cart can still be checked out afterwards.
+func (p *Prepared) Preview(datacenter string) (*Preview, error) {
+	if !p.Assigned {
+		return nil, fmt.Errorf("cart %s must be assigned to preview its checkout", p.CartID)
+	}
+
+	resp, err := p.configureDatacenter(datacenter)
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := p.k.GetCheckoutPreview(p.CartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview checkout: %w", err)
+	}
+
+	// Remove datacenter configuration if we added it
+	if resp != nil {
+		err = p.k.RemoveItemConfiguration(p.CartID, p.ItemID, resp.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Preview{
+		PlanCode:      p.PlanCode,
+		CartID:        p.CartID,
+		Datacenter:    datacenter,
+		Options:       p.Options,
+		Checkout:      checkout,
+		Prices:        NewPrices(checkout, p.priceDuration, p.o.Quantity),
+		PriceDuration: p.priceDuration,
+		Renewal:       p.Renewal,
+	}, nil
+}
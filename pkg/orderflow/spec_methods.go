@@ -0,0 +1,362 @@
+package orderflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+// LoadSpec reads a spec from a YAML file, unknown fields are an error.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// ParseSpec parses a spec from YAML, unknown fields are an error.
+func ParseSpec(data []byte) (*Spec, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var s Spec
+	err := decoder.Decode(&s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+
+	if s.PlanCode == "" {
+		return nil, fmt.Errorf("%w: planCode is required", ErrInvalidSpec)
+	}
+	if s.Quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidSpec)
+	}
+
+	return &s, nil
+}
+
+// ItemOptions returns the spec options as family=planCode, sorted by family.
+func (s Spec) ItemOptions() []string {
+	var itemOptions []string
+	for _, family := range slices.Sorted(maps.Keys(s.Options)) {
+		itemOptions = append(itemOptions, fmt.Sprintf("%s=%s", family, s.Options[family]))
+	}
+
+	return itemOptions
+}
+
+// Apply sets the non zero spec values in o.
+func (s Spec) Apply(o *Options) {
+	o.PlanCode = s.PlanCode
+	if s.Country != "" {
+		o.Subsidiary = s.Country
+	}
+	if len(s.Datacenters) > 0 {
+		o.Datacenters = s.Datacenters
+	}
+	if s.Quantity > 0 {
+		o.Quantity = s.Quantity
+	}
+	if s.PriceMode != "" {
+		o.PriceMode = s.PriceMode
+	}
+	if s.PriceDuration != "" {
+		o.PriceDuration = s.PriceDuration
+	}
+	if len(s.Options) > 0 {
+		o.ItemOptions = s.ItemOptions()
+	}
+	if len(s.Configurations) > 0 {
+		o.ItemConfigurations = maps.Clone(s.Configurations)
+	}
+	if s.AutoPay != nil {
+		o.AutoPay = *s.AutoPay
+	}
+	if s.Budget != nil {
+		o.Budget = &Budget{
+			MaxMonthly: s.Budget.MaxMonthly,
+			MaxSetup:   s.Budget.MaxSetup,
+			MaxTotal:   s.Budget.MaxTotal,
+			Currencies: s.Budget.Currencies,
+		}
+	}
+}
+
+// ValidateOptions validates the plan, item options, item configurations and datacenters of o
+// against the live catalog of its subsidiary, they are reported by spec field name.
+// It validates the order actually placed once a spec is overridden by flags.
+func ValidateOptions(k *kimsufi.Service, o Options) error {
+	var plan *specPlan
+	if kimsufi.IsVPSPlanCode(o.PlanCode) {
+		catalog, err := k.ListVPSServers(o.Subsidiary)
+		if err != nil {
+			return fmt.Errorf("failed to get VPS catalog: %w", err)
+		}
+		if p := catalog.GetVPSPlan(o.PlanCode); p != nil {
+			plan = newVPSSpecPlan(*p)
+		}
+	} else {
+		catalog, err := k.ListServers(o.Subsidiary)
+		if err != nil {
+			return fmt.Errorf("failed to get catalog: %w", err)
+		}
+		if p := catalog.GetPlan(o.PlanCode); p != nil {
+			plan = newEcoSpecPlan(*p)
+		}
+	}
+
+	if plan == nil {
+		return fmt.Errorf("%w: planCode: plan %q not found in the %s catalog", ErrInvalidSpec, o.PlanCode, o.Subsidiary)
+	}
+
+	return validateOptions(o, *plan)
+}
+
+// validateOptions checks every value of o against the plan, all the errors are returned.
+func validateOptions(o Options, plan specPlan) error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidSpec, fmt.Sprintf(format, args...)))
+	}
+
+	if o.PriceMode != "" && len(plan.PriceModes) > 0 && !slices.Contains(plan.PriceModes, o.PriceMode) {
+		invalid("priceMode: %q is not a price mode of plan %s (allowed values: %s)", o.PriceMode, plan.PlanCode, strings.Join(plan.PriceModes, ", "))
+	}
+
+	if o.PriceDuration != "" && len(plan.PriceDurations) > 0 && !slices.ContainsFunc(plan.PriceDurations, func(d string) bool { return sameDuration(d, o.PriceDuration) }) {
+		invalid("priceDuration: %q is not a price duration of plan %s (allowed values: %s)", o.PriceDuration, plan.PlanCode, strings.Join(plan.PriceDurations, ", "))
+	}
+
+	families := map[string]bool{}
+	for _, option := range o.ItemOptions {
+		if option == AnyOption {
+			continue
+		}
+
+		family, value, found := strings.Cut(option, "=")
+		if !found {
+			invalid("options: %q is not a family=option pair", option)
+			continue
+		}
+
+		f := plan.addonFamily(family)
+		if f == nil {
+			invalid("options.%s: plan %s has no %q option family (allowed values: %s)", family, plan.PlanCode, family, strings.Join(plan.addonFamilyNames(), ", "))
+			continue
+		}
+		families[f.Name] = true
+		if value != AnyOption && !slices.Contains(f.Addons, value) {
+			invalid("options.%s: %q is not an option of plan %s (allowed values: %s)", family, value, plan.PlanCode, strings.Join(append(slices.Clone(f.Addons), AnyOption), ", "))
+		}
+	}
+
+	// Mandatory families are only filled automatically with all their options (any) or when they have a single one
+	if !slices.Contains(o.ItemOptions, AnyOption) {
+		for _, f := range plan.AddonFamilies {
+			if f.Mandatory && !families[f.Name] && len(f.Addons) > 1 {
+				invalid("options.%s: mandatory option family of plan %s is missing (allowed values: %s)", f.Name, plan.PlanCode, strings.Join(append(slices.Clone(f.Addons), AnyOption), ", "))
+			}
+		}
+	}
+
+	for _, label := range slices.Sorted(maps.Keys(o.ItemConfigurations)) {
+		value := o.ItemConfigurations[label]
+
+		c := plan.configuration(label)
+		if c == nil {
+			invalid("configurations.%s: plan %s has no %q configuration (allowed values: %s)", label, plan.PlanCode, label, strings.Join(plan.configurationNames(), ", "))
+			continue
+		}
+		if !c.IsCustom && len(c.Values) > 0 && !slices.Contains(c.Values, value) {
+			invalid("configurations.%s: %q is not allowed for plan %s (allowed values: %s)", label, value, plan.PlanCode, strings.Join(c.Values, ", "))
+		}
+	}
+
+	// Datacenters are set from the datacenters list, the region from the endpoint, single values automatically
+	for _, c := range plan.Configurations {
+		if !c.IsMandatory || len(c.Values) == 1 {
+			continue
+		}
+		switch c.Name {
+		case kimsufiorder.ConfigurationLabelDatacenter, kimsufiorder.ConfigurationLabelVPSDatacenter, kimsufiorder.ConfigurationLabelRegion:
+			continue
+		}
+		if _, found := o.ItemConfigurations[c.Name]; found {
+			continue
+		}
+
+		if c.IsCustom || len(c.Values) == 0 {
+			invalid("configurations.%s: mandatory configuration of plan %s is missing", c.Name, plan.PlanCode)
+		} else {
+			invalid("configurations.%s: mandatory configuration of plan %s is missing (allowed values: %s)", c.Name, plan.PlanCode, strings.Join(c.Values, ", "))
+		}
+	}
+
+	datacenters := plan.configuration(kimsufiorder.ConfigurationLabelDatacenter)
+	if datacenters == nil {
+		datacenters = plan.configuration(kimsufiorder.ConfigurationLabelVPSDatacenter)
+	}
+	if datacenters != nil && datacenters.IsMandatory && len(o.Datacenters) == 0 {
+		invalid("datacenters: plan %s requires a datacenter (allowed values: %s)", plan.PlanCode, strings.Join(append(slices.Clone(datacenters.Values), AnyDatacenter), ", "))
+	}
+	if datacenters != nil && len(datacenters.Values) > 0 {
+		for i, dc := range o.Datacenters {
+			if dc != AnyDatacenter && !slices.ContainsFunc(datacenters.Values, func(v string) bool { return strings.EqualFold(v, dc) }) {
+				invalid("datacenters[%d]: %q is not a datacenter of plan %s (allowed values: %s)", i, dc, plan.PlanCode, strings.Join(append(slices.Clone(datacenters.Values), AnyDatacenter), ", "))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func newEcoSpecPlan(p kimsuficatalog.Plan) *specPlan {
+	plan := &specPlan{
+		PlanCode:       p.PlanCode,
+		AddonFamilies:  p.AddonFamilies,
+		Configurations: p.Configurations,
+	}
+	for _, price := range p.Pricings {
+		if !slices.Contains(plan.PriceModes, price.Mode) {
+			plan.PriceModes = append(plan.PriceModes, price.Mode)
+		}
+		plan.addPriceDuration(price.Interval, price.IntervalUnit)
+	}
+
+	return plan
+}
+
+func newVPSSpecPlan(p kimsuficatalog.VPSPlan) *specPlan {
+	plan := &specPlan{PlanCode: p.PlanCode}
+	for _, f := range p.AddonFamilies {
+		plan.AddonFamilies = append(plan.AddonFamilies, kimsuficatalog.PlanAddonFamily(f))
+	}
+	for _, c := range p.Configurations {
+		plan.Configurations = append(plan.Configurations, kimsuficatalog.PlanConfiguration{
+			Name:        c.Name,
+			IsCustom:    c.IsCustom,
+			IsMandatory: c.IsMandatory,
+			Values:      c.Values,
+		})
+	}
+	for _, price := range p.Pricings {
+		if !slices.Contains(plan.PriceModes, price.Mode) {
+			plan.PriceModes = append(plan.PriceModes, price.Mode)
+		}
+		plan.addPriceDuration(price.Interval, price.IntervalUnit)
+	}
+
+	return plan
+}
+
+// addPriceDuration adds the duration of a price billed every interval unit, installation prices have none.
+func (p *specPlan) addPriceDuration(interval int, unit string) {
+	months := interval
+	switch unit {
+	case "month":
+	case "year":
+		months = interval * 12
+	default:
+		return
+	}
+	if months <= 0 {
+		return
+	}
+
+	d := fmt.Sprintf("P%dM", months)
+	if !slices.Contains(p.PriceDurations, d) {
+		p.PriceDurations = append(p.PriceDurations, d)
+	}
+}
+
+// durationMonths returns the number of months of an ISO 8601 price duration given in months or years (e.g. P1M, P1Y),
+// false for other durations.
+func durationMonths(duration string) (int, bool) {
+	value, found := strings.CutPrefix(duration, "P")
+	if !found || len(value) < 2 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	switch value[len(value)-1] {
+	case 'M':
+		return n, true
+	case 'Y':
+		return n * 12, true
+	default:
+		return 0, false
+	}
+}
+
+// sameDuration returns true if both ISO 8601 durations are equal, P12M being the same as P1Y.
+func sameDuration(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	monthsA, okA := durationMonths(a)
+	monthsB, okB := durationMonths(b)
+
+	return okA && okB && monthsA == monthsB
+}
+
+// addonFamily returns the addon family by name, VPS family aliases are accepted (e.g. os for vps_os).
+func (p specPlan) addonFamily(name string) *kimsuficatalog.PlanAddonFamily {
+	for _, f := range p.AddonFamilies {
+		if f.Name == name || f.Name == vpsOptionFamily(name) {
+			return &f
+		}
+	}
+
+	return nil
+}
+
+func (p specPlan) addonFamilyNames() []string {
+	var names []string
+	for _, f := range p.AddonFamilies {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+func (p specPlan) configuration(name string) *kimsuficatalog.PlanConfiguration {
+	for _, c := range p.Configurations {
+		if c.Name == name {
+			return &c
+		}
+	}
+
+	return nil
+}
+
+func (p specPlan) configurationNames() []string {
+	var names []string
+	for _, c := range p.Configurations {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
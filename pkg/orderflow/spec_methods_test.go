@@ -0,0 +1,229 @@
+package orderflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+func TestParseSpec(t *testing.T) {
+	data := `
+planCode: 24sk50
+country: FR
+datacenters: [rbx, gra]
+quantity: 2
+options:
+  storage: softraid-2x2000sa-24sk50
+  memory: ram-32g-ecc-2133-24sk50
+configurations:
+  region: europe
+autoPay: true
+budget:
+  maxMonthly: 30
+`
+
+	s, err := ParseSpec([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseSpec() failed: %v", err)
+	}
+
+	o := Options{PlanCode: "other", Subsidiary: "IE", Quantity: 1, PriceMode: "default"}
+	s.Apply(&o)
+
+	expected := Options{
+		PlanCode:           "24sk50",
+		Subsidiary:         "FR",
+		Datacenters:        []string{"rbx", "gra"},
+		Quantity:           2,
+		PriceMode:          "default",
+		ItemOptions:        []string{"memory=ram-32g-ecc-2133-24sk50", "storage=softraid-2x2000sa-24sk50"},
+		ItemConfigurations: map[string]string{"region": "europe"},
+		AutoPay:            true,
+		Budget:             &Budget{MaxMonthly: 30},
+	}
+	if diff := cmp.Diff(expected, o); diff != "" {
+		t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, invalid := range []string{"datacenter: rbx\nplanCode: 24sk50", "quantity: 1"} {
+		_, err := ParseSpec([]byte(invalid))
+		if !errors.Is(err, ErrInvalidSpec) {
+			t.Errorf("ParseSpec(%q) error = %v, want %v", invalid, err, ErrInvalidSpec)
+		}
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	plan := specPlan{
+		PlanCode: "24sk50",
+		AddonFamilies: []kimsuficatalog.PlanAddonFamily{
+			{Name: "memory", Addons: []string{"ram-32g-ecc-2133-24sk50"}},
+			{Name: "storage", Addons: []string{"softraid-2x2000sa-24sk50", "softraid-2x480ssd-24sk50"}},
+		},
+		Configurations: []kimsuficatalog.PlanConfiguration{
+			{Name: "dedicated_datacenter", Values: []string{"rbx", "gra"}},
+			{Name: "region", Values: []string{"europe"}},
+		},
+		PriceModes: []string{"default"},
+	}
+
+	testCases := []struct {
+		name     string
+		spec     Spec
+		expected []string
+	}{
+		{
+			name: "valid",
+			spec: Spec{
+				PlanCode:       "24sk50",
+				Datacenters:    []string{"RBX", AnyDatacenter},
+				Options:        map[string]string{"memory": "ram-32g-ecc-2133-24sk50", "storage": AnyOption},
+				Configurations: map[string]string{"region": "europe"},
+			},
+		},
+		{
+			name: "invalid values",
+			spec: Spec{
+				PlanCode:       "24sk50",
+				PriceMode:      "upfront12",
+				Datacenters:    []string{"bhs"},
+				Options:        map[string]string{"memroy": "ram-32g-ecc-2133-24sk50", "storage": "softraid-2x4000sa-24sk50"},
+				Configurations: map[string]string{"region": "north_america"},
+			},
+			expected: []string{
+				`priceMode: "upfront12" is not a price mode of plan 24sk50 (allowed values: default)`,
+				`options.memroy: plan 24sk50 has no "memroy" option family (allowed values: memory, storage)`,
+				`options.storage: "softraid-2x4000sa-24sk50" is not an option of plan 24sk50 (allowed values: softraid-2x2000sa-24sk50, softraid-2x480ssd-24sk50, any)`,
+				`configurations.region: "north_america" is not allowed for plan 24sk50 (allowed values: europe)`,
+				`datacenters[0]: "bhs" is not a datacenter of plan 24sk50 (allowed values: rbx, gra, any)`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSpec(tc.spec, plan)
+
+			var got []string
+			if err != nil {
+				for _, line := range strings.Split(err.Error(), "\n") {
+					got = append(got, strings.TrimPrefix(line, ErrInvalidSpec.Error()+": "))
+				}
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	plan := specPlan{
+		PlanCode: "24ska01",
+		AddonFamilies: []kimsuficatalog.PlanAddonFamily{
+			{Name: "storage", Addons: []string{"softraid-2x2000sa-24ska01", "softraid-2x480ssd-24ska01"}},
+		},
+	}
+
+	// Spec options overridden by --plan-code and --item-option
+	o := Options{
+		PlanCode:    "24ska01",
+		ItemOptions: []string{"storage=softraid-2x2000sa-24ska01", "storage=softraid-2x2000sa-24sk50", "memory"},
+	}
+
+	err := validateOptions(o, plan)
+
+	var got []string
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			got = append(got, strings.TrimPrefix(line, ErrInvalidSpec.Error()+": "))
+		}
+	}
+
+	expected := []string{
+		`options.storage: "softraid-2x2000sa-24sk50" is not an option of plan 24ska01 (allowed values: softraid-2x2000sa-24ska01, softraid-2x480ssd-24ska01, any)`,
+		`options: "memory" is not a family=option pair`,
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("validateOptions() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSpecValidateMandatory(t *testing.T) {
+	plan := specPlan{
+		PlanCode: "24sk50",
+		AddonFamilies: []kimsuficatalog.PlanAddonFamily{
+			{Name: "memory", Mandatory: true, Addons: []string{"ram-32g-ecc-2133-24sk50", "ram-64g-ecc-2133-24sk50"}},
+			{Name: "bandwidth", Mandatory: true, Addons: []string{"bandwidth-300-24sk"}},
+			{Name: "storage", Addons: []string{"softraid-2x2000sa-24sk50", "softraid-2x480ssd-24sk50"}},
+		},
+		Configurations: []kimsuficatalog.PlanConfiguration{
+			{Name: "dedicated_datacenter", IsMandatory: true, Values: []string{"rbx", "gra"}},
+			{Name: "dedicated_os", IsMandatory: true, Values: []string{"none_64.en", "debian12_64"}},
+			{Name: "region", IsMandatory: true, Values: []string{"europe", "north_america"}},
+		},
+		PriceModes:     []string{"default"},
+		PriceDurations: []string{"P1M", "P12M"},
+	}
+
+	testCases := []struct {
+		name     string
+		spec     Spec
+		expected []string
+	}{
+		{
+			name: "valid",
+			spec: Spec{
+				PlanCode:       "24sk50",
+				Datacenters:    []string{"rbx"},
+				PriceDuration:  "P1Y",
+				Options:        map[string]string{"memory": AnyOption},
+				Configurations: map[string]string{"dedicated_os": "none_64.en"},
+			},
+		},
+		{
+			name: "missing values",
+			spec: Spec{
+				PlanCode:      "24sk50",
+				PriceDuration: "P6M",
+				Options:       map[string]string{"storage": "softraid-2x2000sa-24sk50"},
+			},
+			expected: []string{
+				`priceDuration: "P6M" is not a price duration of plan 24sk50 (allowed values: P1M, P12M)`,
+				`options.memory: mandatory option family of plan 24sk50 is missing (allowed values: ram-32g-ecc-2133-24sk50, ram-64g-ecc-2133-24sk50, any)`,
+				`configurations.dedicated_os: mandatory configuration of plan 24sk50 is missing (allowed values: none_64.en, debian12_64)`,
+				`datacenters: plan 24sk50 requires a datacenter (allowed values: rbx, gra, any)`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSpec(tc.spec, plan)
+
+			var got []string
+			if err != nil {
+				for _, line := range strings.Split(err.Error(), "\n") {
+					got = append(got, strings.TrimPrefix(line, ErrInvalidSpec.Error()+": "))
+				}
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// validateSpec checks the spec values against the plan, as ValidateOptions does once the spec is applied.
+func validateSpec(s Spec, plan specPlan) error {
+	var o Options
+	s.Apply(&o)
+
+	return validateOptions(o, plan)
+}
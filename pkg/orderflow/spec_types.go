@@ -0,0 +1,52 @@
+package orderflow
+
+import (
+	"errors"
+
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+var (
+	// ErrInvalidSpec is returned when a spec does not match the catalog.
+	ErrInvalidSpec = errors.New("invalid order spec")
+)
+
+// Spec is a declarative order, meant to be kept in a file and reviewed, see LoadSpec.
+// Zero values are left to the defaults.
+type Spec struct {
+	PlanCode string `yaml:"planCode"`
+	// Country is the OVH subsidiary, see Options.Subsidiary.
+	Country string `yaml:"country,omitempty"`
+	// Datacenters are tried in order, see Options.Datacenters.
+	Datacenters []string `yaml:"datacenters,omitempty"`
+	Quantity    int      `yaml:"quantity,omitempty"`
+
+	PriceMode     string `yaml:"priceMode,omitempty"`
+	PriceDuration string `yaml:"priceDuration,omitempty"`
+
+	// Options are the item options by family (e.g. memory: ram-64g-noecc-2133-24ska01).
+	Options map[string]string `yaml:"options,omitempty"`
+	// Configurations are the item configurations by label (e.g. region: europe).
+	Configurations map[string]string `yaml:"configurations,omitempty"`
+
+	AutoPay *bool       `yaml:"autoPay,omitempty"`
+	Budget  *SpecBudget `yaml:"budget,omitempty"`
+}
+
+// SpecBudget are the budget limits of a spec, see Budget.
+type SpecBudget struct {
+	MaxMonthly float64  `yaml:"maxMonthly,omitempty"`
+	MaxSetup   float64  `yaml:"maxSetup,omitempty"`
+	MaxTotal   float64  `yaml:"maxTotal,omitempty"`
+	Currencies []string `yaml:"currencies,omitempty"`
+}
+
+// specPlan is the part of an Eco or VPS catalog plan a spec is validated against.
+type specPlan struct {
+	PlanCode       string
+	AddonFamilies  []kimsuficatalog.PlanAddonFamily
+	Configurations []kimsuficatalog.PlanConfiguration
+	PriceModes     []string
+	// PriceDurations are the ISO 8601 durations of the plan prices, in months (e.g. P1M, P12M).
+	PriceDurations []string
+}
@@ -0,0 +1,26 @@
+package orderstate
+
+import "slices"
+
+// Count returns the number of orders placed for any of the planCodes.
+func (s State) Count(planCodes ...string) int {
+	count := 0
+	for _, o := range s.Orders {
+		if slices.Contains(planCodes, o.PlanCode) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Last returns the last order placed for any of the planCodes, or nil if none was placed.
+func (s State) Last(planCodes ...string) *Order {
+	for i := len(s.Orders) - 1; i >= 0; i-- {
+		if slices.Contains(planCodes, s.Orders[i].PlanCode) {
+			return &s.Orders[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package orderstate
+
+import "time"
+
+const (
+	// FileName is the default order state file name inside the data directory.
+	FileName = "orders.json"
+
+	EventTypeOrderCompleted = "order.completed"
+	EventTypeOrderFailed    = "order.failed"
+)
+
+// State lists the orders already placed by the watcher.
+// It prevents placing the same order again after a restart.
+type State struct {
+	Orders []Order `json:"orders"`
+}
+
+// Order is an order placed by the watcher.
+type Order struct {
+	Time       time.Time `json:"time"`
+	Endpoint   string    `json:"endpoint"`
+	Subsidiary string    `json:"subsidiary"`
+	PlanCode   string    `json:"planCode"`
+	Datacenter string    `json:"datacenter"`
+	OrderID    int       `json:"orderId,omitempty"`
+	URL        string    `json:"url,omitempty"`
+}
@@ -0,0 +1,111 @@
+package orderstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Store persists the order state in a JSON file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a new Store backed by the file at path.
+// If path is empty, the default order state file is used.
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		p, err := xdg.DataFile(FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find order state file: %w", err)
+		}
+		path = p
+	}
+
+	s := &Store{
+		path: path,
+	}
+
+	return s, nil
+}
+
+// Path returns the path of the order state file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the order state.
+// A missing order state file is not an error and returns an empty state.
+func (s *Store) Load() (State, error) {
+	var state State
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return state, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	return state, nil
+}
+
+// Add records the order in the order state file.
+func (s *Store) Add(order Order) (State, error) {
+	state, err := s.Load()
+	if err != nil {
+		return state, err
+	}
+
+	state.Orders = append(state.Orders, order)
+
+	return state, s.write(state)
+}
+
+// write replaces the order state file content with state.
+// The file is written next to the order state file and renamed over it.
+func (s *Store) write(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	_, err = f.Write(data)
+	if err != nil {
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(f.Name(), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
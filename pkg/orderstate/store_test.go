@@ -0,0 +1,52 @@
+package orderstate
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "state", FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load() of a missing file failed: %v", err)
+	}
+	if state.Count("24ska01") != 0 || state.Last("24ska01") != nil {
+		t.Errorf("expected an empty state, got %v", state)
+	}
+
+	t0 := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	orders := []Order{
+		{Time: t0, Endpoint: "ovh-eu", Subsidiary: "FR", PlanCode: "24ska01", Datacenter: "gra", OrderID: 1},
+		{Time: t0.Add(time.Hour), Endpoint: "ovh-eu", Subsidiary: "FR", PlanCode: "24sk50", Datacenter: "rbx", OrderID: 2},
+		{Time: t0.Add(2 * time.Hour), Endpoint: "ovh-eu", Subsidiary: "FR", PlanCode: "24ska01", Datacenter: "rbx", OrderID: 3},
+	}
+	for _, o := range orders {
+		_, err := s.Add(o)
+		if err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+	}
+
+	state, err = s.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if diff := cmp.Diff(orders, state.Orders); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+	if got := state.Count("24ska01"); got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+	if diff := cmp.Diff(&orders[2], state.Last("24ska01")); diff != "" {
+		t.Errorf("Last() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,173 @@
+package ordertrack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+)
+
+// Fetch returns the current state of the order, k must be authenticated.
+func Fetch(k *kimsufi.Service, orderID int, now time.Time) (*Snapshot, error) {
+	status, err := k.GetOrderStatus(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d status: %w", orderID, err)
+	}
+
+	s, err := fetch(k, orderID, status, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Details, err = k.GetOrderDetails(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d details: %w", orderID, err)
+	}
+
+	return s, nil
+}
+
+// List returns the current state of the orders matching the filter, newest first, without their details.
+// The status of each order is fetched first, so that the orders of other statuses are not fetched.
+// k must be authenticated.
+func List(k *kimsufi.Service, f Filter, now time.Time) ([]Snapshot, error) {
+	orderIDs, err := k.ListOrders(f.Since, f.Until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, orderID := range orderIDs {
+		status, err := k.GetOrderStatus(orderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order %d status: %w", orderID, err)
+		}
+		if !f.matchStatus(status) {
+			continue
+		}
+
+		s, err := fetch(k, orderID, status, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Match(*s) {
+			snapshots = append(snapshots, *s)
+		}
+	}
+
+	slices.SortFunc(snapshots, func(a, b Snapshot) int {
+		return b.Order.Date.Compare(a.Order.Date)
+	})
+
+	return snapshots, nil
+}
+
+// fetch returns the current state of the order with the given status, without its details.
+func fetch(k *kimsufi.Service, orderID int, status string, now time.Time) (*Snapshot, error) {
+	order, err := k.GetOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+
+	return &Snapshot{
+		Time:   now,
+		Order:  *order,
+		Status: status,
+	}, nil
+}
+
+// Match returns true when the order matches the filter.
+func (f Filter) Match(s Snapshot) bool {
+	if !f.Since.IsZero() && s.Order.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && s.Order.Date.After(f.Until) {
+		return false
+	}
+
+	return f.matchStatus(s.Status)
+}
+
+// matchStatus returns true when status is one of the filter statuses, or when the filter has none.
+func (f Filter) matchStatus(status string) bool {
+	return len(f.Statuses) == 0 || slices.ContainsFunc(f.Statuses, func(s string) bool { return strings.EqualFold(s, status) })
+}
+
+// IsUnpaidSince returns true when the order is not paid and was placed at least d before the snapshot time.
+func (s Snapshot) IsUnpaidSince(d time.Duration) bool {
+	return s.Status == kimsufiorder.OrderStatusNotPaid && s.Time.Sub(s.Order.Date) >= d
+}
+
+// IsPaid returns true once the order is paid.
+func (s Snapshot) IsPaid() bool {
+	switch s.Status {
+	case kimsufiorder.OrderStatusNotPaid, kimsufiorder.OrderStatusUnknown, kimsufiorder.OrderStatusCancelled, kimsufiorder.OrderStatusCancelling:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsFinal returns true when the order status will not change anymore.
+func (s Snapshot) IsFinal() bool {
+	return s.Status == kimsufiorder.OrderStatusDelivered || s.Status == kimsufiorder.OrderStatusCancelled
+}
+
+// Payment returns the payment state of the order.
+func (s Snapshot) Payment() string {
+	if s.IsPaid() {
+		return "paid"
+	}
+
+	return "not paid"
+}
+
+// Description returns the description of the ordered items.
+func (s Snapshot) Description() string {
+	var descriptions []string
+	for _, d := range s.Details {
+		if d.Description != "" {
+			descriptions = append(descriptions, d.Description)
+		}
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// Events returns an event for each step reached since the previous snapshot, or for the current status when previous is nil.
+func (s Snapshot) Events(previous *Snapshot) []notifier.Event {
+	if previous != nil && previous.Status == s.Status {
+		return nil
+	}
+
+	var events []notifier.Event
+	events = append(events, s.event(EventTypeOrderStatus, fmt.Sprintf("Order %d is %s", s.Order.OrderID, s.Status)))
+
+	if s.IsPaid() && (previous == nil || !previous.IsPaid()) {
+		events = append(events, s.event(EventTypeOrderPaid, fmt.Sprintf("Order %d is paid", s.Order.OrderID)))
+	}
+
+	switch s.Status {
+	case kimsufiorder.OrderStatusDelivered:
+		events = append(events, s.event(EventTypeOrderDelivered, fmt.Sprintf("Order %d is delivered", s.Order.OrderID)))
+	case kimsufiorder.OrderStatusCancelled:
+		events = append(events, s.event(EventTypeOrderCancelled, fmt.Sprintf("Order %d is cancelled", s.Order.OrderID)))
+	}
+
+	return events
+}
+
+func (s Snapshot) event(eventType, title string) notifier.Event {
+	return notifier.Event{
+		Time:    s.Time,
+		Type:    eventType,
+		Title:   title,
+		Message: fmt.Sprintf("%s, %s, %s\n%s", s.Description(), s.Payment(), s.Order.PriceWithTax.Text, s.Order.URL),
+		Data:    s,
+	}
+}
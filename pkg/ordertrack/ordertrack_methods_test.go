@@ -0,0 +1,173 @@
+package ordertrack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ovh/go-ovh/ovh"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+func TestSnapshotEvents(t *testing.T) {
+	snapshot := func(status string) *Snapshot {
+		return &Snapshot{
+			Time:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Order:  kimsufiorder.MeOrder{OrderID: 42},
+			Status: status,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		previous *Snapshot
+		current  *Snapshot
+		expected []string
+	}{
+		{
+			name:     "first snapshot not paid",
+			current:  snapshot(kimsufiorder.OrderStatusNotPaid),
+			expected: []string{EventTypeOrderStatus},
+		},
+		{
+			name:     "unchanged",
+			previous: snapshot(kimsufiorder.OrderStatusDelivering),
+			current:  snapshot(kimsufiorder.OrderStatusDelivering),
+		},
+		{
+			name:     "paid",
+			previous: snapshot(kimsufiorder.OrderStatusNotPaid),
+			current:  snapshot(kimsufiorder.OrderStatusChecking),
+			expected: []string{EventTypeOrderStatus, EventTypeOrderPaid},
+		},
+		{
+			name:     "delivered",
+			previous: snapshot(kimsufiorder.OrderStatusDelivering),
+			current:  snapshot(kimsufiorder.OrderStatusDelivered),
+			expected: []string{EventTypeOrderStatus, EventTypeOrderDelivered},
+		},
+		{
+			name:     "cancelled",
+			previous: snapshot(kimsufiorder.OrderStatusNotPaid),
+			current:  snapshot(kimsufiorder.OrderStatusCancelled),
+			expected: []string{EventTypeOrderStatus, EventTypeOrderCancelled},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, e := range tc.current.Events(tc.previous) {
+				got = append(got, e.Type)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Events() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	s := Snapshot{
+		Time:   now,
+		Order:  kimsufiorder.MeOrder{OrderID: 42, Date: now.Add(-48 * time.Hour)},
+		Status: kimsufiorder.OrderStatusNotPaid,
+	}
+
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{
+			name:     "no filter",
+			expected: true,
+		},
+		{
+			name:     "status",
+			filter:   Filter{Statuses: []string{"notpaid", kimsufiorder.OrderStatusDelivered}},
+			expected: true,
+		},
+		{
+			name:   "other status",
+			filter: Filter{Statuses: []string{kimsufiorder.OrderStatusDelivered}},
+		},
+		{
+			name:     "in range",
+			filter:   Filter{Since: now.Add(-72 * time.Hour), Until: now},
+			expected: true,
+		},
+		{
+			name:   "too old",
+			filter: Filter{Since: now.Add(-24 * time.Hour)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Match(s); got != tc.expected {
+				t.Errorf("Match() = %t, want %t", got, tc.expected)
+			}
+		})
+	}
+
+	if !s.IsUnpaidSince(24 * time.Hour) {
+		t.Errorf("IsUnpaidSince(24h) = false, want true")
+	}
+	if s.IsUnpaidSince(72 * time.Hour) {
+		t.Errorf("IsUnpaidSince(72h) = true, want false")
+	}
+}
+
+func TestListFetchesMatchingStatusesOnly(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/auth/time":
+			fmt.Fprint(w, time.Now().Unix())
+		case "/me/order":
+			fmt.Fprint(w, "[1,2]")
+		case "/me/order/1/status":
+			fmt.Fprintf(w, "%q", kimsufiorder.OrderStatusNotPaid)
+		case "/me/order/2/status":
+			fmt.Fprintf(w, "%q", kimsufiorder.OrderStatusDelivered)
+		default:
+			fetched = append(fetched, r.URL.Path)
+			fmt.Fprint(w, `{"orderId":1,"date":"2025-01-01T00:00:00Z"}`)
+		}
+	}))
+	defer server.Close()
+
+	ovh.Endpoints["ordertrack-test"] = server.URL
+	defer delete(ovh.Endpoints, "ordertrack-test")
+
+	k, err := kimsufi.NewService("ordertrack-test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := List(k, Filter{Statuses: []string{kimsufiorder.OrderStatusNotPaid}}, time.Now())
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Order.OrderID != 1 {
+		t.Errorf("List() = %+v, want order 1", snapshots)
+	}
+	if diff := cmp.Diff([]string{"/me/order/1"}, fetched); diff != "" {
+		t.Errorf("fetched orders mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,30 @@
+package ordertrack
+
+import (
+	"time"
+
+	kimsufiorder "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/order"
+)
+
+const (
+	EventTypeOrderStatus    = "order.status"
+	EventTypeOrderPaid      = "order.paid"
+	EventTypeOrderDelivered = "order.delivered"
+	EventTypeOrderCancelled = "order.cancelled"
+)
+
+// Snapshot is the state of an order at a given time.
+type Snapshot struct {
+	Time    time.Time                    `json:"time"`
+	Order   kimsufiorder.MeOrder         `json:"order"`
+	Status  string                       `json:"status"`
+	Details []kimsufiorder.MeOrderDetail `json:"details"`
+}
+
+// Filter selects orders, zero values are not filtered on.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	// Statuses are the accepted order statuses, see kimsufiorder.OrderStatus*.
+	Statuses []string
+}
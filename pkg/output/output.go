@@ -0,0 +1,90 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+var (
+	// Formats is the list of supported output formats.
+	Formats = []string{FormatTable, FormatJSON, FormatCSV}
+)
+
+// Validate returns an error if format is not a supported output format.
+func Validate(format string) error {
+	if !slices.Contains(Formats, format) {
+		return fmt.Errorf("invalid output format %q (allowed values: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	return nil
+}
+
+// WriteTable writes rows as an aligned table with an underlined header,
+// the same way other commands display their results.
+func WriteTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+
+	underlines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		underlines = append(underlines, strings.Repeat("-", len(h)))
+	}
+
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	fmt.Fprintln(tw, strings.Join(underlines, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes rows as CSV, with headers as the first record.
+func WriteCSV(w io.Writer, headers []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(headers)
+	if err != nil {
+		return err
+	}
+
+	err = cw.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// WriteJSON writes v as indented JSON.
+func WriteJSON(w io.Writer, v any) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(v)
+}
+
+// Write writes the data in the given format.
+// headers and rows are used for table and CSV formats, v is used for JSON format.
+func Write(w io.Writer, format string, headers []string, rows [][]string, v any) error {
+	switch format {
+	case FormatTable:
+		return WriteTable(w, headers, rows)
+	case FormatCSV:
+		return WriteCSV(w, headers, rows)
+	case FormatJSON:
+		return WriteJSON(w, v)
+	}
+
+	return Validate(format)
+}
@@ -0,0 +1,176 @@
+package pricecompare
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+// Compare returns the offers of the plan in every subsidiary of m, see kimsufi.MultiService.Targets.
+// Subsidiaries not selling the plan are left out, failing subsidiaries are returned as an error with the other offers.
+func Compare(m kimsufi.MultiService, planCode string, parallelism int) (Offers, error) {
+	results := kimsufi.FanOut(m, m.Targets(), parallelism, func(k *kimsufi.Service, t kimsufi.Target) (*kimsuficatalog.Catalog, error) {
+		return k.ListServers(t.Subsidiary)
+	})
+
+	var offers Offers
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", r.Endpoint, r.Subsidiary, r.Err))
+			continue
+		}
+
+		plan := r.Value.GetPlan(planCode)
+		if plan == nil {
+			continue
+		}
+
+		offers = append(offers, NewOffer(r.Target, r.Value.Locale, *plan))
+	}
+
+	return offers, errors.Join(errs...)
+}
+
+// NewOffer returns the offer of the plan in the target subsidiary.
+func NewOffer(target kimsufi.Target, locale kimsuficatalog.Locale, plan kimsuficatalog.Plan) Offer {
+	o := Offer{
+		Target:   target,
+		PlanCode: plan.PlanCode,
+		Name:     strings.Split(plan.InvoiceName, " | ")[0],
+		Currency: locale.CurrencyCode,
+		TaxRate:  locale.TaxRate,
+	}
+
+	monthly := plan.GetFirstPrice()
+	if !reflect.DeepEqual(monthly, kimsuficatalog.PlanPricing{}) {
+		o.Monthly = monthly.GetPrice()
+	}
+	o.Setup = plan.GetSetupPrice().GetPrice()
+
+	return o
+}
+
+// MonthlyWithTax returns the monthly price with tax.
+func (o Offer) MonthlyWithTax() float64 {
+	return withTax(o.Monthly, o.TaxRate)
+}
+
+// SetupWithTax returns the setup fee with tax.
+func (o Offer) SetupWithTax() float64 {
+	return withTax(o.Setup, o.TaxRate)
+}
+
+// Convert sets the Base price of each offer, converted with the rate table.
+// Offers priced in a currency missing from the table are left unconverted and returned as an error.
+func (o Offers) Convert(t RateTable) error {
+	var errs []error
+	for i := range o {
+		monthly, err := t.Convert(o[i].MonthlyWithTax(), o[i].Currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", o[i].Endpoint, o[i].Subsidiary, err))
+			continue
+		}
+
+		setup, err := t.Convert(o[i].SetupWithTax(), o[i].Currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", o[i].Endpoint, o[i].Subsidiary, err))
+			continue
+		}
+
+		o[i].Base = &BasePrice{
+			Currency: t.Base,
+			Monthly:  monthly,
+			Setup:    setup,
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sort sorts the offers cheapest first by monthly price with tax.
+// Converted offers are compared in the base currency and come first,
+// the others are grouped by currency.
+func (o Offers) Sort() {
+	sort.SliceStable(o, func(i, j int) bool {
+		a, b := o[i], o[j]
+		if (a.Base != nil) != (b.Base != nil) {
+			return a.Base != nil
+		}
+		if a.Base != nil {
+			return a.Base.Monthly < b.Base.Monthly
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+
+		return a.MonthlyWithTax() < b.MonthlyWithTax()
+	})
+}
+
+// LoadRateTable reads the rate table YAML file at path.
+func LoadRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := ParseRateTable(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return t, nil
+}
+
+// ParseRateTable parses a rate table from YAML, unknown fields are an error.
+func ParseRateTable(data []byte) (*RateTable, error) {
+	t := &RateTable{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Base == "" {
+		return nil, fmt.Errorf("base currency is required")
+	}
+	for currency, rate := range t.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid rate %v for %s: must be positive", rate, currency)
+		}
+	}
+
+	return t, nil
+}
+
+// Convert converts the amount in the currency to the base currency.
+func (t RateTable) Convert(amount float64, currency string) (float64, error) {
+	if strings.EqualFold(currency, t.Base) {
+		return amount, nil
+	}
+
+	for c, rate := range t.Rates {
+		if strings.EqualFold(c, currency) {
+			return amount * rate, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w for %s to %s", ErrNoRate, currency, t.Base)
+}
+
+// withTax returns the price with the tax rate in percent.
+func withTax(price float64, taxRate int) float64 {
+	return price * (1 + float64(taxRate)/100)
+}
@@ -0,0 +1,105 @@
+package pricecompare
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+func TestNewOffer(t *testing.T) {
+	plan := kimsuficatalog.Plan{
+		PlanCode:    "24ska01",
+		InvoiceName: "KS-A | Intel i7-6700k",
+		Pricings: []kimsuficatalog.PlanPricing{
+			{Capacities: []string{"installation"}, Mode: "default", Price: 999000000},
+			{Capacities: []string{"renew"}, Interval: 1, IntervalUnit: "month", Mode: "default", Phase: 1, Type: "rental", Strategy: "tiered", Price: 1599000000},
+		},
+	}
+	target := kimsufi.Target{Endpoint: "ovh-eu", Subsidiary: "FR"}
+
+	got := NewOffer(target, kimsuficatalog.Locale{CurrencyCode: "EUR", TaxRate: 20}, plan)
+
+	expected := Offer{Target: target, PlanCode: "24ska01", Name: "KS-A", Currency: "EUR", TaxRate: 20, Monthly: 15.99, Setup: 9.99}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("NewOffer() mismatch (-want +got):\n%s", diff)
+	}
+	if withTax := got.MonthlyWithTax(); withTax < 19.18 || withTax > 19.19 {
+		t.Errorf("MonthlyWithTax() = %v, want 19.188", withTax)
+	}
+}
+
+func TestParseRateTable(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		expected      *RateTable
+		expectedError bool
+	}{
+		{
+			name:     "valid",
+			data:     "base: EUR\nrates:\n  GBP: 1.17\n  CAD: 0.68\n",
+			expected: &RateTable{Base: "EUR", Rates: map[string]float64{"GBP": 1.17, "CAD": 0.68}},
+		},
+		{
+			name:          "missing base",
+			data:          "rates:\n  GBP: 1.17\n",
+			expectedError: true,
+		},
+		{
+			name:          "negative rate",
+			data:          "base: EUR\nrates:\n  GBP: -1\n",
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			data:          "base: EUR\nrate:\n  GBP: 1.17\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseRateTable([]byte(tc.data))
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("ParseRateTable() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRateTable() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("ParseRateTable() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOffersConvertAndSort(t *testing.T) {
+	offers := Offers{
+		{Target: kimsufi.Target{Subsidiary: "US"}, Currency: "USD", Monthly: 10},
+		{Target: kimsufi.Target{Subsidiary: "FR"}, Currency: "EUR", TaxRate: 20, Monthly: 10},
+		{Target: kimsufi.Target{Subsidiary: "GB"}, Currency: "GBP", TaxRate: 20, Monthly: 8},
+		{Target: kimsufi.Target{Subsidiary: "CA"}, Currency: "CAD", Monthly: 14},
+	}
+
+	err := offers.Convert(RateTable{Base: "EUR", Rates: map[string]float64{"GBP": 1.2, "CAD": 0.5}})
+	if !errors.Is(err, ErrNoRate) {
+		t.Errorf("Convert() returned %v, want %v for USD", err, ErrNoRate)
+	}
+
+	offers.Sort()
+
+	var got []string
+	for _, o := range offers {
+		got = append(got, o.Subsidiary)
+	}
+	if diff := cmp.Diff([]string{"CA", "GB", "FR", "US"}, got); diff != "" {
+		t.Errorf("Sort() order mismatch (-want +got):\n%s", diff)
+	}
+}
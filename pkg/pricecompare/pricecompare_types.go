@@ -0,0 +1,47 @@
+package pricecompare
+
+import (
+	"errors"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi"
+)
+
+var (
+	// ErrNoRate is returned when converting a currency missing from the rate table.
+	ErrNoRate = errors.New("no rate")
+)
+
+// Offer is the price of a plan in a subsidiary.
+type Offer struct {
+	kimsufi.Target
+	PlanCode string `json:"planCode"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+	// TaxRate is the subsidiary tax rate, in percent.
+	TaxRate int `json:"taxRate"`
+	// Monthly is the monthly price, without tax.
+	Monthly float64 `json:"monthly"`
+	// Setup is the setup fee, without tax.
+	Setup float64 `json:"setup"`
+
+	// Base is the offer price converted to the base currency of the rate table, nil when not converted.
+	Base *BasePrice `json:"base,omitempty"`
+}
+
+// BasePrice is an offer price converted to a base currency, with tax.
+type BasePrice struct {
+	Currency string  `json:"currency"`
+	Monthly  float64 `json:"monthly"`
+	Setup    float64 `json:"setup"`
+}
+
+// Offers are the offers of a plan.
+type Offers []Offer
+
+// RateTable converts prices to a base currency.
+type RateTable struct {
+	// Base is the currency prices are converted to.
+	Base string `yaml:"base"`
+	// Rates are the value of one unit of each currency in the base currency.
+	Rates map[string]float64 `yaml:"rates"`
+}
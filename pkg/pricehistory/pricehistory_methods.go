@@ -0,0 +1,248 @@
+package pricehistory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+// NewPricesFromCatalog returns the first price of each plan and addon of an Eco catalog.
+func NewPricesFromCatalog(endpoint string, now time.Time, c kimsuficatalog.Catalog) Prices {
+	var plans, addons []catalogItem
+
+	for _, plan := range c.Plans {
+		price := plan.GetFirstPrice()
+		item := catalogItem{
+			PlanCode:     plan.PlanCode,
+			InvoiceName:  plan.InvoiceName,
+			Category:     plan.GetCategory(),
+			Priced:       len(plan.Pricings) > 0,
+			Price:        price.GetPrice(),
+			Interval:     price.Interval,
+			IntervalUnit: price.IntervalUnit,
+		}
+		for _, family := range plan.AddonFamilies {
+			item.Addons = append(item.Addons, family.Addons...)
+		}
+		plans = append(plans, item)
+	}
+
+	for _, addon := range c.Addons {
+		price := addon.GetFirstPrice()
+		addons = append(addons, catalogItem{
+			PlanCode:     addon.PlanCode,
+			InvoiceName:  addon.InvoiceName,
+			Priced:       len(addon.Pricings) > 0,
+			Price:        price.GetPrice(),
+			Interval:     price.Interval,
+			IntervalUnit: price.IntervalUnit,
+		})
+	}
+
+	return newPrices(endpoint, now, KindEco, c.Locale, plans, addons)
+}
+
+// NewPricesFromVPSCatalog returns the first price of each plan and addon of a VPS catalog.
+func NewPricesFromVPSCatalog(endpoint string, now time.Time, c kimsuficatalog.VPSCatalog) Prices {
+	var plans, addons []catalogItem
+
+	for _, plan := range c.Plans {
+		price := plan.GetFirstPrice()
+		item := catalogItem{
+			PlanCode:     plan.PlanCode,
+			InvoiceName:  plan.InvoiceName,
+			Category:     plan.GetCategory(),
+			Priced:       len(plan.Pricings) > 0,
+			Price:        price.GetPrice(),
+			Interval:     price.Interval,
+			IntervalUnit: price.IntervalUnit,
+		}
+		for _, family := range plan.AddonFamilies {
+			item.Addons = append(item.Addons, family.Addons...)
+		}
+		plans = append(plans, item)
+	}
+
+	for _, addon := range c.Addons {
+		price := addon.GetFirstPrice()
+		addons = append(addons, catalogItem{
+			PlanCode:     addon.PlanCode,
+			InvoiceName:  addon.InvoiceName,
+			Priced:       len(addon.Pricings) > 0,
+			Price:        price.GetPrice(),
+			Interval:     price.Interval,
+			IntervalUnit: price.IntervalUnit,
+		})
+	}
+
+	return newPrices(endpoint, now, KindVPS, c.Locale, plans, addons)
+}
+
+// newPrices returns the prices of the plans and addons of a catalog of the given kind.
+// Addons belong to the category of the first plan offering them.
+func newPrices(endpoint string, now time.Time, kind string, locale kimsuficatalog.Locale, plans, addons []catalogItem) Prices {
+	var prices Prices
+
+	base := Price{
+		Time:         now.UTC(),
+		Endpoint:     endpoint,
+		Subsidiary:   locale.Subsidiary,
+		Kind:         kind,
+		CurrencyCode: locale.CurrencyCode,
+	}
+
+	addonCategories := make(map[string]string)
+	for _, plan := range plans {
+		for _, addon := range plan.Addons {
+			if _, ok := addonCategories[addon]; !ok {
+				addonCategories[addon] = plan.Category
+			}
+		}
+	}
+
+	for _, item := range plans {
+		if item.Priced {
+			prices = append(prices, item.price(base, TypePlan, item.Category))
+		}
+	}
+
+	for _, item := range addons {
+		if item.Priced {
+			prices = append(prices, item.price(base, TypeAddon, addonCategories[item.PlanCode]))
+		}
+	}
+
+	return prices
+}
+
+// price returns the price of the item, base holding the catalog fields.
+func (i catalogItem) price(base Price, itemType, category string) Price {
+	p := base
+	p.Type = itemType
+	p.PlanCode = i.PlanCode
+	p.InvoiceName = i.InvoiceName
+	p.Category = category
+	p.Price = i.Price
+	p.Interval = i.Interval
+	p.IntervalUnit = i.IntervalUnit
+
+	return p
+}
+
+// Key identifies the plan or addon a price belongs to.
+func (p Price) Key() string {
+	return strings.Join([]string{p.Endpoint, strings.ToUpper(p.Subsidiary), p.Kind, p.Type, p.PlanCode}, "/")
+}
+
+// String returns the price with its currency and period, the period is left out when unknown.
+func (p Price) String() string {
+	switch {
+	case p.IntervalUnit == "":
+		return fmt.Sprintf("%.2f %s", p.Price, p.CurrencyCode)
+	case p.Interval <= 1:
+		return fmt.Sprintf("%.2f %s/%s", p.Price, p.CurrencyCode, p.IntervalUnit)
+	default:
+		return fmt.Sprintf("%.2f %s/%d %ss", p.Price, p.CurrencyCode, p.Interval, p.IntervalUnit)
+	}
+}
+
+// Match returns true when the price is selected by the filter.
+func (f Filter) Match(p Price) bool {
+	if f.Endpoint != "" && f.Endpoint != p.Endpoint {
+		return false
+	}
+	if f.Subsidiary != "" && !strings.EqualFold(f.Subsidiary, p.Subsidiary) {
+		return false
+	}
+	if f.PlanCode != "" && f.PlanCode != p.PlanCode {
+		return false
+	}
+	if f.Category != "" && f.Category != p.Category {
+		return false
+	}
+	if !f.Since.IsZero() && p.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && p.Time.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the prices selected by the filter.
+func (prices Prices) Filter(f Filter) Prices {
+	var filtered Prices
+	for _, p := range prices {
+		if f.Match(p) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// Sort sorts prices by time, keeping the recording order for equal times.
+func (prices Prices) Sort() {
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].Time.Before(prices[j].Time)
+	})
+}
+
+// Latest returns the last recorded price of each plan and addon by key.
+// Prices must be sorted by time.
+func (prices Prices) Latest() map[string]Price {
+	latest := make(map[string]Price)
+	for _, p := range prices {
+		latest[p.Key()] = p
+	}
+
+	return latest
+}
+
+// Changes returns each price along with the one recorded before it for the same plan or addon.
+// Prices must be sorted by time.
+func (prices Prices) Changes() Changes {
+	var changes Changes
+
+	latest := make(map[string]Price)
+	for _, p := range prices {
+		c := Change{Current: p}
+		if previous, found := latest[p.Key()]; found {
+			c.Previous = &previous
+		}
+		latest[p.Key()] = p
+		changes = append(changes, c)
+	}
+
+	return changes
+}
+
+// Delta returns the price difference, zero for a first observation.
+func (c Change) Delta() float64 {
+	if c.Previous == nil {
+		return 0
+	}
+
+	return c.Current.Price - c.Previous.Price
+}
+
+// IsDrop returns true when the price decreased.
+func (c Change) IsDrop() bool {
+	return c.Delta() < 0
+}
+
+// Filter returns the changes whose current price is selected by the filter.
+func (changes Changes) Filter(f Filter) Changes {
+	var filtered Changes
+	for _, c := range changes {
+		if f.Match(c.Current) {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,75 @@
+package pricehistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	kimsuficatalog "github.com/TheoBrigitte/kimsufi-notifier/pkg/kimsufi/catalog"
+)
+
+func TestNewPricesFromCatalogAddonCategory(t *testing.T) {
+	pricings := []kimsuficatalog.PlanPricing{{Price: 100000000}}
+	c := kimsuficatalog.Catalog{
+		Locale: kimsuficatalog.Locale{CurrencyCode: "EUR", Subsidiary: "FR"},
+		Plans: []kimsuficatalog.Plan{
+			{
+				PlanCode:      "24ska01",
+				Pricings:      pricings,
+				Blobs:         kimsuficatalog.PlanBlobs{Commercial: kimsuficatalog.PlanBlobsCommercial{Range: "kimsufi"}},
+				AddonFamilies: []kimsuficatalog.PlanAddonFamily{{Name: "memory", Addons: []string{"ram-32g-24ska01"}}},
+			},
+		},
+		Addons: []kimsuficatalog.Addon{
+			{PlanCode: "ram-32g-24ska01", Pricings: pricings},
+			{PlanCode: "orphan", Pricings: pricings},
+		},
+	}
+
+	got := make(map[string]string)
+	for _, p := range NewPricesFromCatalog("ovh-eu", time.Now(), c) {
+		got[p.PlanCode] = p.Category
+	}
+
+	expected := map[string]string{
+		"24ska01":         "kimsufi",
+		"ram-32g-24ska01": "kimsufi",
+		"orphan":          "",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("categories mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPriceString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		price    Price
+		expected string
+	}{
+		{
+			name:     "monthly",
+			price:    Price{Price: 4.99, CurrencyCode: "EUR", Interval: 1, IntervalUnit: "month"},
+			expected: "4.99 EUR/month",
+		},
+		{
+			name:     "several months",
+			price:    Price{Price: 54, CurrencyCode: "EUR", Interval: 12, IntervalUnit: "month"},
+			expected: "54.00 EUR/12 months",
+		},
+		{
+			name:     "unknown interval",
+			price:    Price{Price: 4.99, CurrencyCode: "EUR"},
+			expected: "4.99 EUR",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.price.String(); got != tc.expected {
+				t.Errorf("String() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package pricehistory
+
+import "time"
+
+const (
+	// FileName is the default price history file name inside the data directory.
+	FileName = "prices.jsonl"
+
+	KindEco = "eco"
+	KindVPS = "vps"
+
+	TypePlan  = "plan"
+	TypeAddon = "addon"
+)
+
+type Prices []Price
+
+// Price records the first price of a plan or addon at a point in time.
+// Like availability transitions, a price is only recorded when it changes,
+// the first observation of a plan or addon is always recorded.
+type Price struct {
+	Time         time.Time `json:"time"`
+	Endpoint     string    `json:"endpoint"`
+	Subsidiary   string    `json:"subsidiary"`
+	Kind         string    `json:"kind"`
+	Type         string    `json:"type"`
+	PlanCode     string    `json:"planCode"`
+	InvoiceName  string    `json:"invoiceName"`
+	Category     string    `json:"category,omitempty"`
+	Price        float64   `json:"price"`
+	CurrencyCode string    `json:"currencyCode"`
+	// Interval and IntervalUnit are the period the price is paid for (e.g. 1 month), unknown for older records.
+	Interval     int    `json:"interval,omitempty"`
+	IntervalUnit string `json:"intervalUnit,omitempty"`
+}
+
+// catalogItem is a plan or addon of an Eco or VPS catalog, with its first price.
+type catalogItem struct {
+	PlanCode    string
+	InvoiceName string
+	Category    string
+	// Addons lists the addons offered with a plan.
+	Addons []string
+	// Priced is false when the item has no price.
+	Priced       bool
+	Price        float64
+	Interval     int
+	IntervalUnit string
+}
+
+type Changes []Change
+
+// Change is a recorded price, along with the price it replaced.
+// Previous is nil for the first observation.
+type Change struct {
+	Previous *Price `json:"previous,omitempty"`
+	Current  Price  `json:"current"`
+}
+
+// Filter selects prices.
+// Empty fields match everything.
+type Filter struct {
+	Endpoint   string
+	Subsidiary string
+	PlanCode   string
+	Category   string
+	Since      time.Time
+	Until      time.Time
+}
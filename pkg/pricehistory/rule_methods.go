@@ -0,0 +1,162 @@
+package pricehistory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/notifier"
+)
+
+// ParseRule parses a rule given as a comma separated list of key=value filters and a condition,
+// e.g. "plan=24sk50,subsidiary=FR,below=20" or "category=rise,change".
+// Supported filters are plan, category, subsidiary, kind (eco, vps), type (plan, addon) and name.
+// Supported conditions are below=<price>, change and drop.
+func ParseRule(value string) (Rule, error) {
+	var r Rule
+
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key, v, _ := strings.Cut(field, "=")
+		switch key {
+		case "name":
+			r.Name = v
+		case "plan", "planCode":
+			r.PlanCode = v
+		case "category":
+			r.Category = v
+		case "subsidiary", "country":
+			r.Subsidiary = strings.ToUpper(v)
+		case "kind":
+			if v != KindEco && v != KindVPS {
+				return Rule{}, fmt.Errorf("invalid rule %q: invalid kind %q (allowed values: %s, %s)", value, v, KindEco, KindVPS)
+			}
+			r.Kind = v
+		case "type":
+			if v != TypePlan && v != TypeAddon {
+				return Rule{}, fmt.Errorf("invalid rule %q: invalid type %q (allowed values: %s, %s)", value, v, TypePlan, TypeAddon)
+			}
+			r.Type = v
+		case ConditionBelow:
+			threshold, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return Rule{}, fmt.Errorf("invalid rule %q: invalid price %q: %w", value, v, err)
+			}
+			r.Condition = ConditionBelow
+			r.Threshold = threshold
+		case ConditionChange, ConditionDrop:
+			r.Condition = key
+		default:
+			return Rule{}, fmt.Errorf("invalid rule %q: unknown field %q", value, key)
+		}
+	}
+
+	if r.Condition == "" {
+		return Rule{}, fmt.Errorf("invalid rule %q: missing condition (allowed values: %s)", value, strings.Join(Conditions, ", "))
+	}
+
+	if r.Name == "" {
+		r.Name = value
+	}
+
+	return r, nil
+}
+
+// ParseRules parses each value with ParseRule.
+func ParseRules(values []string) (Rules, error) {
+	var rules Rules
+	for _, value := range values {
+		r, err := ParseRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// Match returns true when the change matches the rule filters and condition.
+func (r Rule) Match(c Change) bool {
+	p := c.Current
+
+	if r.PlanCode != "" && r.PlanCode != p.PlanCode {
+		return false
+	}
+	if r.Category != "" && r.Category != p.Category {
+		return false
+	}
+	if r.Subsidiary != "" && !strings.EqualFold(r.Subsidiary, p.Subsidiary) {
+		return false
+	}
+	if r.Kind != "" && r.Kind != p.Kind {
+		return false
+	}
+	if r.Type != "" && r.Type != p.Type {
+		return false
+	}
+
+	switch r.Condition {
+	case ConditionBelow:
+		// Only alert when crossing the threshold, not on every change below it.
+		return p.Price < r.Threshold && (c.Previous == nil || c.Previous.Price >= r.Threshold)
+	case ConditionChange:
+		return c.Previous != nil && c.Previous.Price != p.Price
+	case ConditionDrop:
+		return c.IsDrop()
+	}
+
+	return false
+}
+
+// Evaluate returns the alerts raised by the changes, at most one per change and rule.
+func (rules Rules) Evaluate(changes Changes) []Alert {
+	var alerts []Alert
+	for _, c := range changes {
+		for _, r := range rules {
+			if r.Match(c) {
+				alerts = append(alerts, Alert{Rule: r, Change: c})
+			}
+		}
+	}
+
+	return alerts
+}
+
+// Event returns the notification event of the alert.
+func (a Alert) Event(now time.Time) notifier.Event {
+	p := a.Change.Current
+
+	message := p.String()
+	if a.Change.Previous != nil {
+		message = fmt.Sprintf("%s -> %s", a.Change.Previous.String(), p.String())
+	}
+
+	name := p.PlanCode
+	if p.InvoiceName != "" && p.InvoiceName != p.PlanCode {
+		name = fmt.Sprintf("%s (%s)", p.PlanCode, p.InvoiceName)
+	}
+
+	return notifier.Event{
+		Time:    now,
+		Type:    EventTypePriceAlert,
+		Title:   fmt.Sprintf("Price of %s %s in %s matched rule %s", p.Type, name, p.Subsidiary, a.Rule.Name),
+		Message: message,
+		Data:    a,
+	}
+}
+
+// Events returns the notification events of the alerts.
+func Events(alerts []Alert, now time.Time) []notifier.Event {
+	var events []notifier.Event
+	for _, a := range alerts {
+		events = append(events, a.Event(now))
+	}
+
+	return events
+}
@@ -0,0 +1,188 @@
+package pricehistory
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var t0 = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+func price(minutes int, planCode, category string, value float64) Price {
+	return Price{
+		Time:         t0.Add(time.Duration(minutes) * time.Minute),
+		Endpoint:     "ovh-eu",
+		Subsidiary:   "FR",
+		Kind:         KindEco,
+		Type:         TypePlan,
+		PlanCode:     planCode,
+		InvoiceName:  planCode,
+		Category:     category,
+		Price:        value,
+		CurrencyCode: "EUR",
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected Rule
+		err      bool
+	}{
+		{
+			name:     "below",
+			value:    "plan=24sk50,subsidiary=fr,below=20",
+			expected: Rule{Name: "plan=24sk50,subsidiary=fr,below=20", PlanCode: "24sk50", Subsidiary: "FR", Condition: ConditionBelow, Threshold: 20},
+		},
+		{
+			name:     "change with name",
+			value:    "name=rise,category=rise,change",
+			expected: Rule{Name: "rise", Category: "rise", Condition: ConditionChange},
+		},
+		{
+			name:  "missing condition",
+			value: "plan=24sk50",
+			err:   true,
+		},
+		{
+			name:  "invalid price",
+			value: "below=cheap",
+			err:   true,
+		},
+		{
+			name:  "unknown field",
+			value: "color=red,drop",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseRule(tc.value)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("ParseRule() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	previous := func(p Price) *Price { return &p }
+
+	below := Rule{PlanCode: "24sk50", Subsidiary: "FR", Condition: ConditionBelow, Threshold: 20}
+	change := Rule{Category: "rise", Condition: ConditionChange}
+	drop := Rule{Condition: ConditionDrop}
+
+	testCases := []struct {
+		name     string
+		rule     Rule
+		change   Change
+		expected bool
+	}{
+		{
+			name:     "first observation below threshold",
+			rule:     below,
+			change:   Change{Current: price(0, "24sk50", "kimsufi", 15)},
+			expected: true,
+		},
+		{
+			name:     "crossing threshold",
+			rule:     below,
+			change:   Change{Previous: previous(price(0, "24sk50", "kimsufi", 25)), Current: price(10, "24sk50", "kimsufi", 19.99)},
+			expected: true,
+		},
+		{
+			name:     "already below threshold",
+			rule:     below,
+			change:   Change{Previous: previous(price(0, "24sk50", "kimsufi", 18)), Current: price(10, "24sk50", "kimsufi", 17)},
+			expected: false,
+		},
+		{
+			name:     "other plan",
+			rule:     below,
+			change:   Change{Current: price(0, "24sk40", "kimsufi", 15)},
+			expected: false,
+		},
+		{
+			name:     "category change",
+			rule:     change,
+			change:   Change{Previous: previous(price(0, "24rise01", "rise", 50)), Current: price(10, "24rise01", "rise", 55)},
+			expected: true,
+		},
+		{
+			name:     "category first observation",
+			rule:     change,
+			change:   Change{Current: price(0, "24rise01", "rise", 50)},
+			expected: false,
+		},
+		{
+			name:     "increase is not a drop",
+			rule:     drop,
+			change:   Change{Previous: previous(price(0, "24rise01", "rise", 50)), Current: price(10, "24rise01", "rise", 55)},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.rule.Match(tc.change)
+			if got != tc.expected {
+				t.Errorf("Match() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStoreRecord(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := price(0, "24sk50", "kimsufi", 25)
+	changes, err := s.Record(Prices{first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(Changes{{Current: first}}, changes); diff != "" {
+		t.Errorf("Record() mismatch (-want +got):\n%s", diff)
+	}
+
+	changes, err = s.Record(Prices{price(10, "24sk50", "kimsufi", 25)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no change for an unchanged price, got %v", changes)
+	}
+
+	cheaper := price(20, "24sk50", "kimsufi", 19)
+	changes, err = s.Record(Prices{cheaper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(Changes{{Previous: &first, Current: cheaper}}, changes); diff != "" {
+		t.Errorf("Record() mismatch (-want +got):\n%s", diff)
+	}
+
+	prices, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(Prices{first, cheaper}, prices); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package pricehistory
+
+const (
+	EventTypePriceAlert = "price.alert"
+
+	// ConditionBelow matches when a price drops below a threshold.
+	ConditionBelow = "below"
+	// ConditionChange matches any price change.
+	ConditionChange = "change"
+	// ConditionDrop matches any price decrease.
+	ConditionDrop = "drop"
+)
+
+var (
+	// Conditions is the list of supported rule conditions.
+	Conditions = []string{ConditionBelow, ConditionChange, ConditionDrop}
+)
+
+type Rules []Rule
+
+// Rule is a price alerting rule evaluated against recorded price changes.
+// Empty filter fields match everything.
+type Rule struct {
+	Name       string  `json:"name,omitempty"`
+	PlanCode   string  `json:"planCode,omitempty"`
+	Category   string  `json:"category,omitempty"`
+	Subsidiary string  `json:"subsidiary,omitempty"`
+	Kind       string  `json:"kind,omitempty"`
+	Type       string  `json:"type,omitempty"`
+	Condition  string  `json:"condition"`
+	Threshold  float64 `json:"threshold,omitempty"`
+}
+
+// Alert is a price change which matched a rule.
+type Alert struct {
+	Rule   Rule   `json:"rule"`
+	Change Change `json:"change"`
+}
@@ -0,0 +1,94 @@
+package pricehistory
+
+import (
+	"fmt"
+
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/jsonl"
+	"github.com/TheoBrigitte/kimsufi-notifier/pkg/xdg"
+)
+
+// Store persists prices in a JSON lines file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a new Store backed by the file at path.
+// If path is empty, the default price history file is used.
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		p, err := xdg.DataFile(FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find price history file: %w", err)
+		}
+		path = p
+	}
+
+	s := &Store{
+		path: path,
+	}
+
+	return s, nil
+}
+
+// Path returns the path of the price history file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns all the recorded prices, sorted by time.
+// A missing price history file is not an error and returns no prices.
+func (s *Store) Load() (Prices, error) {
+	values, err := jsonl.Read[Price](s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := Prices(values)
+	prices.Sort()
+
+	return prices, nil
+}
+
+// Append writes the prices at the end of the price history file.
+func (s *Store) Append(prices ...Price) error {
+	return jsonl.Append(s.path, prices...)
+}
+
+// Record compares the prices with the last known price of each plan
+// and addon and appends the ones which changed.
+// It returns the recorded changes.
+func (s *Store) Record(prices Prices) (Changes, error) {
+	recorded, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := recorded.Latest()
+
+	var (
+		changes Changes
+		updated Prices
+	)
+	for _, p := range prices {
+		c := Change{Current: p}
+
+		previous, found := latest[p.Key()]
+		if found {
+			if previous.Price == p.Price {
+				continue
+			}
+			c.Previous = &previous
+		}
+
+		latest[p.Key()] = p
+		changes = append(changes, c)
+		updated = append(updated, p)
+	}
+
+	err = s.Append(updated...)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,86 @@
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// AppName is the directory name used under each base directory.
+	AppName = "kimsufi-notifier"
+)
+
+// DataHome returns the application data directory.
+// It uses $XDG_DATA_HOME when set and falls back to ~/.local/share.
+func DataHome() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dir, AppName), nil
+}
+
+// DataFile returns the path of name inside the application data directory.
+func DataFile(name string) (string, error) {
+	dir, err := DataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// ConfigHome returns the application configuration directory.
+// It uses $XDG_CONFIG_HOME when set and falls back to ~/.config.
+func ConfigHome() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, AppName), nil
+}
+
+// ConfigFile returns the path of name inside the application configuration directory.
+func ConfigFile(name string) (string, error) {
+	dir, err := ConfigHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// CacheHome returns the application cache directory.
+// It uses $XDG_CACHE_HOME when set and falls back to ~/.cache.
+func CacheHome() (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+
+	return filepath.Join(dir, AppName), nil
+}
+
+// CacheFile returns the path of name inside the application cache directory.
+func CacheFile(name string) (string, error) {
+	dir, err := CacheHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}